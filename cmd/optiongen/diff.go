@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines shown around a change.
+const context = 3
+
+// op is a line of an edit script: ' ' keeps, '-' deletes and '+' inserts.
+type op struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns the changes from old to new in the unified format,
+// labelling the files as a/path and b/path, or an empty string when they
+// are equal.
+func unifiedDiff(path string, old, new []byte) string {
+	a, b := lines(old), lines(new)
+	ops := edits(a, b)
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		// A hunk spans the changes less than two contexts apart.
+		from := max(start-context, 0)
+		end, kept := start, 0
+		for i := start; i < len(ops) && kept <= 2*context; i++ {
+			if ops[i].kind == ' ' {
+				kept++
+				continue
+			}
+			kept = 0
+			end = i + 1
+		}
+		to := min(end+context, len(ops))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", path, path)
+		}
+		oldStart, newStart := 1, 1
+		for _, o := range ops[:from] {
+			if o.kind != '+' {
+				oldStart++
+			}
+			if o.kind != '-' {
+				newStart++
+			}
+		}
+		var oldLen, newLen int
+		for _, o := range ops[from:to] {
+			if o.kind != '+' {
+				oldLen++
+			}
+			if o.kind != '-' {
+				newLen++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLen), hunkRange(newStart, newLen))
+		for _, o := range ops[from:to] {
+			out.WriteByte(o.kind)
+			out.WriteString(o.line)
+			out.WriteByte('\n')
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// hunkRange formats the start and length of a hunk, where an empty range
+// starts at the line before it.
+func hunkRange(start, n int) string {
+	if n == 0 {
+		start--
+	}
+	return fmt.Sprintf("%d,%d", start, n)
+}
+
+func lines(src []byte) []string {
+	if len(src) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+}
+
+// edits returns the shortest edit script turning a into b, derived from the
+// longest common subsequence of the lines.
+func edits(a, b []string) []op {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []op
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/optiongen"
+)
+
+// discover walks the directory tree below root and returns a job per
+// struct marked with optiongen.Marker, configured by the flags in base.
+// Like the go command, it skips testdata, vendor and directories starting
+// with a dot or an underscore.
+func discover(root string, base config) ([]job, error) {
+	if base.typeName != "" {
+		return nil, fmt.Errorf("-type cannot be combined with %s/...", root)
+	}
+	if base.output != "" {
+		return nil, fmt.Errorf("-output cannot be combined with %s/...", root)
+	}
+	var jobs []job
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		name := d.Name()
+		if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		names, err := optiongen.Discover(path)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			cfg := base
+			cfg.typeName = name
+			jobs = append(jobs, job{cfg: cfg, dir: path})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("no struct marked %s below %s", optiongen.Marker, root)
+	}
+	return jobs, nil
+}
+
+// summary collects the files handled by the jobs of a run, reported once
+// all of them are done.
+type summary struct {
+	// job is the job being run.
+	job     job
+	entries []entry
+}
+
+// entry records what happened to one generated file.
+type entry struct {
+	job job
+	// path is the file, or the error of a failed job.
+	path string
+	// status is written, unchanged, removed, diffed or failed.
+	status string
+}
+
+// add records the status of the file at path written by the current job.
+// It does nothing on a nil summary, so single runs stay quiet.
+func (s *summary) add(path, status string) {
+	if s == nil {
+		return
+	}
+	s.entries = append(s.entries, entry{job: s.job, path: path, status: status})
+}
+
+// report prints the files per struct, followed by the totals:
+//
+//	example/optiongen Client
+//		written   example/optiongen/client_options_gen.go
+//		unchanged example/optiongen/client_redacted_gen.go
+//	1 struct: 1 written, 1 unchanged
+func (s *summary) report(w io.Writer) {
+	var last job
+	structs := 0
+	counts := map[string]int{}
+	for _, e := range s.entries {
+		if e.job.cfg.typeName != last.cfg.typeName || e.job.dir != last.dir {
+			fmt.Fprintf(w, "%s %s\n", filepath.ToSlash(e.job.dir), e.job.cfg.typeName)
+			structs++
+			last = e.job
+		}
+		fmt.Fprintf(w, "\t%-9s %s\n", e.status, filepath.ToSlash(e.path))
+		counts[e.status]++
+	}
+	var totals []string
+	for _, status := range []string{"written", "unchanged", "removed", "diffed", "failed"} {
+		if counts[status] > 0 {
+			totals = append(totals, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	noun := "structs"
+	if structs == 1 {
+		noun = "struct"
+	}
+	fmt.Fprintf(w, "%d %s: %s\n", structs, noun, strings.Join(totals, ", "))
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/optiongen"
+)
+
+func TestDiscoverRun(t *testing.T) {
+	root := t.TempDir()
+	sources := map[string]string{
+		"server/server.go":    "package server\n\n//optiongen:options\ntype Server struct {\n\thost string\n}\n",
+		"testdata/fixture.go": "package fixture\n\n//optiongen:options\ntype Fixture struct {\n\thost string\n}\n",
+		"client/client.go":    "package client\n\n//optiongen:options\ntype Client struct {\n\tbaseURL string\n}\n",
+		"client/unmarked.go":  "package client\n\ntype Unmarked struct {\n\tname string\n}\n",
+		".hidden/hidden.go":   "package hidden\n\n//optiongen:options\ntype Hidden struct {\n\tname string\n}\n",
+	}
+	for name, src := range sources {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config{mode: optiongen.ModeOptions}
+	jobs, err := plan(&cfg, []string{root + "/..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].cfg.typeName != "Client" || jobs[1].cfg.typeName != "Server" {
+		t.Fatalf("plan() = %+v, want the jobs of Client and Server", jobs)
+	}
+	if err := runAll(jobs); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"client/client_options_gen.go", "server/server_options_gen.go"} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Error(err)
+		}
+	}
+	var report strings.Builder
+	cfg.summary.report(&report)
+	if got := report.String(); !strings.HasSuffix(got, "2 structs: 2 written\n") {
+		t.Errorf("report =\n%s\nwant the totals of 2 written files", got)
+	}
+}
+
+func TestDiscoverErrors(t *testing.T) {
+	root := t.TempDir()
+	for _, cfg := range []config{{typeName: "Client"}, {output: "gen.go"}, {}} {
+		if _, err := discover(root, cfg); err == nil {
+			t.Errorf("discover(%+v) succeeded", cfg)
+		}
+	}
+}
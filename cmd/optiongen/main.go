@@ -0,0 +1,583 @@
+// Command optiongen generates functional options for the fields of a struct.
+//
+// Usage:
+//
+//	//go:generate go run github.com/StevenCyb/golang-functional-options/cmd/optiongen -type=Client
+//
+// The -mode flag selects the generated code:
+//
+//	options        functional options and a constructor (default)
+//	setters        chainable SetX methods
+//	config         a Config struct with Options and NewWithConfig, building
+//	               on the output of the options mode
+//	constructors   telescoping NewWithX constructors delegating to the
+//	               constructor named by -constructor
+//	interface      a default implementation of the interface named by -type,
+//	               such as DefaultLogger for ILogger, with an option per method
+//	builder        a ClientBuilder with a fluent method per option and Build,
+//	               building on the output of the options mode
+//
+// The code is written to <type>_<mode>_gen.go in the package directory unless
+// -output is set. With -pkg=clientopts the options are written to the
+// clientopts sub-package instead, and accessors for the unexported fields to
+// <type>_accessors_gen.go. With -with-tests, table driven tests for the
+// generated options are written to the same name with a _test.go suffix, and
+// with -with-fuzz a fuzz target feeding random values through the options to
+// <type>_fuzz_gen_test.go.
+//
+// The options are called With<Field>, such as WithHttpTimeout, unless -prefix
+// and -style say otherwise: -prefix=Opt -style=go yields OptHTTPTimeout. A
+// field tagged `option:"func=Timeout"` keeps the option name Timeout.
+//
+// The -unexported flag makes the handling of unexported fields explicit:
+// package keeps their options next to the struct, accessors requires -pkg and
+// its OptionFields methods, and skip generates no options for them, printing
+// a warning per field.
+//
+// With -bridge-setters, the options of fields with an existing method such as
+// SetHeader(header map[string]string) call it instead of assigning the field,
+// keeping its validation and side effects.
+//
+// Options of fields tagged `option:"deprecated=use WithHeaders"` are written
+// to <type>_deprecated_gen.go. Fields tagged `option:"sensitive"` are masked
+// by String and LogValue methods written to <type>_redacted_gen.go. With
+// -env-prefix=MYAPP, <Type>FromEnv is written to <type>_env_gen.go, returning
+// the options for variables such as MYAPP_BASE_URL. With -flags, <Type>Flags
+// is written to <type>_flags_gen.go, defining a flag such as -base-url per
+// option on a flag.FlagSet; adding -pflag defines them on a pflag.FlagSet
+// instead, such as the flags of a cobra command, with the shorthand of fields
+// tagged `option:"short=u"`. With -loaders=json,yaml, <Type>FromJSON and
+// <Type>FromYAML are written to <type>_loaders_gen.go; the YAML loader needs
+// gopkg.in/yaml.v3. The options of an interface implementation are written to
+// <implementation>_options_gen.go.
+//
+// With -plugins=mock, the files emitted by the output plugin mock are written
+// to the package directory as well. Plugins are registered with
+// optiongen.Register by programs embedding the generator, or run as an
+// optiongen-mock executable from the PATH, see optiongen.ExecPlugin.
+//
+// Generated files record a fingerprint of their input and are skipped while
+// it is unchanged, unless -force is set. With -diff nothing is written; a
+// unified diff of the changes is printed instead. With -watch the command
+// keeps running and regenerates whenever a source file of the package, or
+// a file of -templates, is saved.
+//
+// Given a pattern such as ./..., the command generates for every struct
+// below the directory whose doc comment holds the marker //optiongen:options,
+// applying the flags to all of them, and reports the files it produced:
+//
+//	//go:generate go run github.com/StevenCyb/golang-functional-options/cmd/optiongen -with-tests ./...
+//
+// The proto subcommand reads .proto files instead, writing a Go struct per
+// message to <file>_proto_gen.go and the options of the top-level messages
+// next to it:
+//
+//	optiongen proto -out ./config config.proto
+//
+// The report subcommand prints, for the structs selected by -type, ./... or
+// the project configuration, which fields lack a declared option, which
+// have no default and which options no test refers to; -strict turns the
+// gaps into a failure:
+//
+//	optiongen report -strict ./...
+//
+// The migrate subcommand replaces handwritten constructors taking positional
+// parameters with deprecated wrappers in <type>_migrate_gen.go, which pass
+// the parameters to the options of the generated constructor:
+//
+//	optiongen migrate -type=Client -constructor=NewClient -constructors=NewWithBaseURLAndHeaders
+//
+// Instead of a directive per struct, the structs of a project can be listed
+// in .optiongen.yaml, or the file named by -config, which is read when -type
+// is not set:
+//
+//	//go:generate go run github.com/StevenCyb/golang-functional-options/cmd/optiongen
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/optiongen"
+)
+
+// config holds the command line flags.
+type config struct {
+	typeName    string
+	output      string
+	mode        optiongen.Mode
+	doc         string
+	constructor string
+	withTests   bool
+	withFuzz    bool
+	pkg         string
+	templates   string
+	force       bool
+	envPrefix   string
+	flags       bool
+	pflag       bool
+	loaders     string
+	bridge      bool
+	plugins     string
+	prefix      string
+	style       optiongen.Style
+	unexported  optiongen.Unexported
+	diff        bool
+	watch       bool
+	project     string
+	// summary collects the generated files when structs are discovered.
+	summary *summary
+	// exclude and rename come from the project configuration.
+	exclude []string
+	rename  map[string]string
+	// enumPrefix is put in front of the enum types of proto messages, see
+	// optiongen.Struct.PrefixEnums.
+	enumPrefix string
+}
+
+func main() {
+	if cmd, ok := subcommands[arg(os.Args, 1)]; ok {
+		if err := cmd(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "optiongen:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	var cfg config
+	flag.StringVar(&cfg.typeName, "type", "", "name of the struct to generate options for, or of the interface in mode interface")
+	flag.StringVar(&cfg.output, "output", "", "output file, defaults to <type>_<mode>_gen.go")
+	flag.StringVar((*string)(&cfg.mode), "mode", string(optiongen.ModeOptions), "generated code, one of options, setters, config, constructors, interface or builder")
+	flag.StringVar(&cfg.doc, "doc", "", "text/template for the doc comments of generated options, see optiongen.DefaultDocTemplate")
+	flag.StringVar(&cfg.constructor, "constructor", "", `name of the generated constructor, defaults to new<Type>, "-" disables it`)
+	flag.StringVar(&cfg.prefix, "prefix", "", "prefix of the generated options in place of With, such as Opt for OptTimeout")
+	flag.StringVar((*string)(&cfg.style), "style", "", "casing of the generated options, camel for WithHttpTimeout (default) or go for WithHTTPTimeout")
+	flag.StringVar((*string)(&cfg.unexported), "unexported", "", "policy for unexported fields: package keeps the options next to the struct, accessors reaches them from -pkg through OptionFields methods, skip leaves them out with a warning")
+	flag.BoolVar(&cfg.withTests, "with-tests", false, "also generate tests for the options next to the output file")
+	flag.BoolVar(&cfg.withFuzz, "with-fuzz", false, "also generate a fuzz target for the options and validators to <type>_fuzz_gen_test.go")
+	flag.StringVar(&cfg.templates, "templates", "", "comma separated text/template files redefining the blocks of the generated code, see optiongen.Generate")
+	flag.BoolVar(&cfg.force, "force", false, "regenerate files even if their input has not changed")
+	flag.StringVar(&cfg.pkg, "pkg", "", "generate the options into a sub-package of this name, with accessors for the unexported fields")
+	flag.StringVar(&cfg.envPrefix, "env-prefix", "", "also generate <Type>FromEnv reading the options from environment variables with this prefix")
+	flag.BoolVar(&cfg.flags, "flags", false, "also generate <Type>Flags defining a flag.FlagSet flag per option")
+	flag.BoolVar(&cfg.pflag, "pflag", false, "with -flags, define the flags on a github.com/spf13/pflag FlagSet, as used by cobra")
+	flag.StringVar(&cfg.loaders, "loaders", "", "comma separated document formats to generate <Type>FromJSON and <Type>FromYAML loaders for, json or yaml")
+	flag.BoolVar(&cfg.bridge, "bridge-setters", false, "make the options of fields with an existing SetX method call it instead of assigning the field")
+	flag.StringVar(&cfg.plugins, "plugins", "", "comma separated output plugins to run, registered ones or optiongen-<name> executables from the PATH")
+	flag.BoolVar(&cfg.diff, "diff", false, "print a unified diff of the changes instead of writing files")
+	flag.BoolVar(&cfg.watch, "watch", false, "keep running and regenerate whenever a source file of the package changes")
+	flag.StringVar(&cfg.project, "config", "", "project configuration listing the structs to generate for, defaults to "+projectFile+" when -type is not set")
+	flag.Parse()
+
+	jobs, err := plan(&cfg, flag.Args())
+	if err == nil {
+		err = runAll(jobs)
+	}
+	if cfg.summary != nil && len(cfg.summary.entries) > 0 {
+		cfg.summary.report(os.Stderr)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "optiongen:", err)
+		os.Exit(1)
+	}
+	if !cfg.watch {
+		return
+	}
+	var dirs, templates []string
+	for _, j := range jobs {
+		dirs = append(dirs, j.dir)
+		if j.cfg.templates != "" {
+			templates = append(templates, strings.Split(j.cfg.templates, ",")...)
+		}
+	}
+	if cfg.project != "" {
+		templates = append(templates, cfg.project)
+	}
+	err = watch(dirs, templates, func() error {
+		// The project configuration, or the marked structs, may have changed
+		// as well.
+		jobs, err := plan(&cfg, flag.Args())
+		if err != nil {
+			return err
+		}
+		err = runAll(jobs)
+		if cfg.summary != nil {
+			cfg.summary.report(os.Stderr)
+		}
+		return err
+	})
+	fmt.Fprintln(os.Stderr, "optiongen:", err)
+	os.Exit(1)
+}
+
+// plan returns the runs of the generator selected by the flags: one per
+// marked struct for a pattern such as ./..., those of the project
+// configuration unless -type is set, or the single one of -type. The
+// summary of cfg is reset for discovered structs.
+func plan(cfg *config, args []string) ([]job, error) {
+	if len(args) > 0 {
+		if root, ok := strings.CutSuffix(filepath.ToSlash(args[0]), "/..."); ok {
+			cfg.summary = &summary{}
+			return discover(filepath.FromSlash(root), *cfg)
+		}
+	}
+	if cfg.project == "" && cfg.typeName == "" {
+		if _, err := os.Stat(projectFile); err == nil {
+			cfg.project = projectFile
+		}
+	}
+	if cfg.project != "" {
+		return loadProject(cfg.project, *cfg)
+	}
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	return []job{{cfg: *cfg, dir: dir}}, nil
+}
+
+// runAll runs jobs in order and stops at the first failure, unless the
+// structs were discovered: the remaining ones are still generated then and
+// the failures are reported together.
+func runAll(jobs []job) error {
+	var errs []error
+	for _, j := range jobs {
+		if j.cfg.summary != nil {
+			j.cfg.summary.job = j
+		}
+		if err := run(j.cfg, []string{j.dir}); err != nil {
+			err = fmt.Errorf("%s: %w", j.cfg.typeName, err)
+			if j.cfg.summary == nil {
+				return err
+			}
+			j.cfg.summary.add(err.Error(), "failed")
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func run(cfg config, args []string) error {
+	if cfg.typeName == "" {
+		return fmt.Errorf("-type is required")
+	}
+	if cfg.withTests && cfg.mode != optiongen.ModeOptions {
+		return fmt.Errorf("-with-tests needs mode %s", optiongen.ModeOptions)
+	}
+	if cfg.withFuzz && cfg.mode != optiongen.ModeOptions {
+		return fmt.Errorf("-with-fuzz needs mode %s", optiongen.ModeOptions)
+	}
+	if cfg.envPrefix != "" && cfg.mode != optiongen.ModeOptions {
+		return fmt.Errorf("-env-prefix needs mode %s", optiongen.ModeOptions)
+	}
+	if cfg.flags && cfg.mode != optiongen.ModeOptions {
+		return fmt.Errorf("-flags needs mode %s", optiongen.ModeOptions)
+	}
+	if cfg.pflag && !cfg.flags {
+		return fmt.Errorf("-pflag needs -flags")
+	}
+	if cfg.loaders != "" && cfg.mode != optiongen.ModeOptions {
+		return fmt.Errorf("-loaders needs mode %s", optiongen.ModeOptions)
+	}
+	if cfg.bridge && cfg.mode != optiongen.ModeOptions {
+		return fmt.Errorf("-bridge-setters needs mode %s", optiongen.ModeOptions)
+	}
+	if cfg.plugins != "" && cfg.mode != optiongen.ModeOptions {
+		return fmt.Errorf("-plugins needs mode %s", optiongen.ModeOptions)
+	}
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	s, err := load(cfg, dir)
+	if err != nil {
+		return err
+	}
+
+	output := cfg.output
+	if output == "" {
+		output = filepath.Join(dir, cfg.pkg, strings.ToLower(cfg.typeName)+"_"+string(cfg.mode)+"_gen.go")
+	}
+	err = cfg.write(output, s, cfg.mode, func() ([]byte, error) {
+		return optiongen.Generate(s, cfg.mode)
+	})
+	if err != nil {
+		return err
+	}
+
+	if cfg.mode == optiongen.ModeInterface {
+		options := filepath.Join(filepath.Dir(output), strings.ToLower(s.Name)+"_options_gen.go")
+		if err := cfg.write(options, s, optiongen.ModeOptions, func() ([]byte, error) {
+			return optiongen.Generate(s, optiongen.ModeOptions)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if cfg.mode == optiongen.ModeOptions {
+		deprecated := filepath.Join(filepath.Dir(output), strings.ToLower(cfg.typeName)+"_deprecated_gen.go")
+		if err := cfg.writeIf(len(s.DeprecatedFields()) > 0, deprecated, s, optiongen.ModeDeprecated, func() ([]byte, error) {
+			return optiongen.GenerateDeprecated(s)
+		}); err != nil {
+			return err
+		}
+		redacted := filepath.Join(dir, strings.ToLower(cfg.typeName)+"_redacted_gen.go")
+		if err := cfg.writeIf(s.Sensitive(), redacted, s, optiongen.ModeRedacted, func() ([]byte, error) {
+			return optiongen.GenerateRedacted(s)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if cfg.envPrefix != "" {
+		env := filepath.Join(filepath.Dir(output), strings.ToLower(cfg.typeName)+"_env_gen.go")
+		if err := cfg.write(env, s, optiongen.ModeEnv, func() ([]byte, error) {
+			return optiongen.GenerateEnv(s)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if cfg.flags {
+		flags := filepath.Join(filepath.Dir(output), strings.ToLower(cfg.typeName)+"_flags_gen.go")
+		if err := cfg.write(flags, s, optiongen.ModeFlags, func() ([]byte, error) {
+			return optiongen.GenerateFlags(s)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(s.Loaders) > 0 {
+		loaders := filepath.Join(filepath.Dir(output), strings.ToLower(cfg.typeName)+"_loaders_gen.go")
+		if err := cfg.write(loaders, s, optiongen.ModeLoaders, func() ([]byte, error) {
+			return optiongen.GenerateLoaders(s)
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range s.Plugins {
+		files, err := optiongen.RunPlugin(optiongen.LookupPlugin(name), s)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			path := filepath.Join(dir, filepath.FromSlash(f.Name))
+			old, err := os.ReadFile(path)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			if err := cfg.store(path, old, f.Content); err != nil {
+				return err
+			}
+		}
+	}
+
+	if cfg.pkg != "" {
+		// The options of the sub-package depend on the accessors.
+		accessors := filepath.Join(dir, strings.ToLower(cfg.typeName)+"_accessors_gen.go")
+		if err := cfg.write(accessors, s, optiongen.ModeAccessors, func() ([]byte, error) {
+			return optiongen.GenerateAccessors(s)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if cfg.withFuzz {
+		fuzz := filepath.Join(filepath.Dir(output), strings.ToLower(cfg.typeName)+"_fuzz_gen_test.go")
+		if err := cfg.write(fuzz, s, optiongen.ModeFuzz, func() ([]byte, error) {
+			return optiongen.GenerateFuzz(s)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if !cfg.withTests {
+		return nil
+	}
+	return cfg.write(strings.TrimSuffix(output, ".go")+"_test.go", s, optiongen.ModeTests, func() ([]byte, error) {
+		return optiongen.GenerateTests(s)
+	})
+}
+
+// load parses the struct of cfg in dir and applies the flags shaping its
+// model.
+func load(cfg config, dir string) (*optiongen.Struct, error) {
+	parse := optiongen.Parse
+	if cfg.mode == optiongen.ModeInterface {
+		parse = optiongen.ParseInterface
+	}
+	s, err := parse(dir, cfg.typeName)
+	if err != nil {
+		return nil, err
+	}
+	if err := customize(s, cfg); err != nil {
+		return nil, err
+	}
+	if err := unexported(s, cfg); err != nil {
+		return nil, err
+	}
+	s.DocTemplate = cfg.doc
+	s.Prefix = cfg.prefix
+	if cfg.enumPrefix != "" {
+		s.PrefixEnums(cfg.enumPrefix)
+	}
+	if cfg.style != "" && !slices.Contains(optiongen.Styles, cfg.style) {
+		return nil, fmt.Errorf("unknown style %q, want camel or go", cfg.style)
+	}
+	s.Style = cfg.style
+	s.EnvPrefix = cfg.envPrefix
+	s.PFlag = cfg.pflag
+	if cfg.bridge {
+		if len(s.Setters()) == 0 {
+			return nil, fmt.Errorf("-bridge-setters: %s has no SetX methods taking the value of a field", s.Name)
+		}
+		s.BridgeSetters = true
+	}
+	if cfg.loaders != "" {
+		for _, l := range strings.Split(cfg.loaders, ",") {
+			s.Loaders = append(s.Loaders, optiongen.Loader(strings.TrimSpace(l)))
+		}
+	}
+	if cfg.plugins != "" {
+		for _, name := range strings.Split(cfg.plugins, ",") {
+			s.Plugins = append(s.Plugins, strings.TrimSpace(name))
+		}
+	}
+	if cfg.templates != "" {
+		for _, path := range strings.Split(cfg.templates, ",") {
+			text, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			s.Overrides += string(text) + "\n"
+		}
+	}
+	switch cfg.constructor {
+	case "":
+	case "-":
+		s.Constructor = ""
+	default:
+		s.Constructor = cfg.constructor
+	}
+	if cfg.pkg != "" {
+		if err := intoPackage(s, cfg, dir); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// unexported applies the policy of -unexported to s. Without it the
+// options stay in the package of the struct, or reach the fields through
+// accessors with -pkg.
+func unexported(s *optiongen.Struct, cfg config) error {
+	switch cfg.unexported {
+	case "":
+	case optiongen.UnexportedPackage:
+		if cfg.pkg != "" {
+			return fmt.Errorf("-unexported=%s keeps the options in the package of %s, which -pkg moves", cfg.unexported, s.Name)
+		}
+	case optiongen.UnexportedAccessors:
+		if cfg.pkg == "" {
+			return fmt.Errorf("-unexported=%s needs -pkg, the options of the package of %s assign the fields directly", cfg.unexported, s.Name)
+		}
+	case optiongen.UnexportedSkip:
+		for _, path := range s.SkipUnexported() {
+			fmt.Fprintf(os.Stderr, "optiongen: %s: skipping unexported field %s\n", s.Name, path)
+		}
+	default:
+		return fmt.Errorf("unknown policy %q for unexported fields, want package, accessors or skip", cfg.unexported)
+	}
+	return nil
+}
+
+// write stores the output of generate at path, unless the file already
+// records the fingerprint of the input for mode or -force is set. Unchanged
+// files are left untouched, so repeated runs stay fast and produce no diff.
+// With -diff the changes are printed instead.
+func (cfg config) write(path string, s *optiongen.Struct, mode optiongen.Mode, generate func() ([]byte, error)) error {
+	old, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if !cfg.force && err == nil && optiongen.Fingerprinted(old) == optiongen.Fingerprint(s, mode) {
+		cfg.summary.add(path, "unchanged")
+		return nil
+	}
+	src, err := generate()
+	if err != nil {
+		return err
+	}
+	return cfg.store(path, old, src)
+}
+
+// store writes src to path unless it equals old, the current content, or
+// prints the diff with -diff. The files of plugins are stored directly, as
+// they carry no fingerprint.
+func (cfg config) store(path string, old, src []byte) error {
+	if bytes.Equal(old, src) {
+		cfg.summary.add(path, "unchanged")
+		return nil
+	}
+	if cfg.diff {
+		cfg.summary.add(path, "diffed")
+		_, err := fmt.Fprint(os.Stdout, unifiedDiff(filepath.ToSlash(path), old, src))
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, src, 0o644); err != nil {
+		return err
+	}
+	cfg.summary.add(path, "written")
+	return nil
+}
+
+// writeIf writes the output of generate to path when needed is set, or
+// removes a file generated earlier otherwise. Such files, like the options
+// of fields no longer deprecated, would redeclare generated code.
+func (cfg config) writeIf(needed bool, path string, s *optiongen.Struct, mode optiongen.Mode, generate func() ([]byte, error)) error {
+	if needed {
+		return cfg.write(path, s, mode, generate)
+	}
+	src, err := os.ReadFile(path)
+	if err != nil || optiongen.Fingerprinted(src) == "" {
+		return nil
+	}
+	if cfg.diff {
+		cfg.summary.add(path, "diffed")
+		_, err := fmt.Fprint(os.Stdout, unifiedDiff(filepath.ToSlash(path), src, nil))
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	cfg.summary.add(path, "removed")
+	return nil
+}
+
+// intoPackage prepares s for generating the options into the sub-package
+// cfg.pkg of dir. The constructor of the sub-package is called New unless
+// -constructor is set.
+func intoPackage(s *optiongen.Struct, cfg config, dir string) error {
+	out, err := exec.Command("go", "list", "-f", "{{.ImportPath}}", dir).Output()
+	if err != nil {
+		return fmt.Errorf("resolving import path of %s: %w", dir, err)
+	}
+	s.ImportPath = strings.TrimSpace(string(out))
+	s.Pkg = cfg.pkg
+	if cfg.constructor == "" {
+		s.Constructor = "New"
+	}
+	return nil
+}
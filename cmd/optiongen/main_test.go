@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/optiongen"
+)
+
+const serverSource = `package app
+
+type Server struct {
+	host string
+	port int
+}
+`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config
+		file string
+		want string
+	}{
+		{name: "options", cfg: config{typeName: "Server", mode: optiongen.ModeOptions}, file: "server_options_gen.go", want: "func WithHost(host string)"},
+		{name: "setters", cfg: config{typeName: "Server", mode: optiongen.ModeSetters}, file: "server_setters_gen.go", want: "SetHost(host string)"},
+		{name: "config", cfg: config{typeName: "Server", mode: optiongen.ModeConfig}, file: "server_config_gen.go", want: "func (cfg *Config) Options()"},
+		{name: "constructors", cfg: config{typeName: "Server", mode: optiongen.ModeConstructors}, file: "server_constructors_gen.go", want: "func NewWithHostAndPort(host string, port int)"},
+		{name: "with tests", cfg: config{typeName: "Server", mode: optiongen.ModeOptions, withTests: true}, file: "server_options_gen_test.go", want: "func TestServerOptionsSetFields("},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "server.go"), []byte(serverSource), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := run(tt.cfg, []string{dir}); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("%s does not contain %q:\n%s", tt.file, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRunRequiresType(t *testing.T) {
+	if err := run(config{mode: optiongen.ModeOptions}, nil); err == nil || err.Error() != "-type is required" {
+		t.Errorf("run() error = %v, want -type is required", err)
+	}
+}
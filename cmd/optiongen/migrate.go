@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/optiongen"
+)
+
+// runMigrate implements the migrate subcommand: it writes deprecated
+// wrappers of the handwritten constructors of -constructors to
+// <type>_migrate_gen.go and removes the originals from their files, so
+// existing callers keep compiling against the options:
+//
+//	optiongen migrate -type=Client -constructor=NewClient -constructors=NewWithBaseURLAndHeaders,NewWithBaseURLHeadersAndLogger
+//
+// The options themselves are generated as before, by optiongen -type, with
+// the same exported -constructor.
+func runMigrate(args []string) error {
+	var cfg config
+	var constructors string
+	fset := flag.NewFlagSet("optiongen migrate", flag.ExitOnError)
+	fset.StringVar(&cfg.typeName, "type", "", "name of the struct the constructors create")
+	fset.StringVar(&constructors, "constructors", "", "comma separated constructors taking positional parameters to replace")
+	fset.StringVar(&cfg.output, "output", "", "output file, defaults to <type>_migrate_gen.go")
+	fset.StringVar(&cfg.constructor, "constructor", "", "name of the exported constructor generated with the options, which the wrappers call")
+	fset.StringVar(&cfg.prefix, "prefix", "", "prefix of the generated options in place of With")
+	fset.StringVar((*string)(&cfg.style), "style", "", "casing of the generated options, camel or go")
+	fset.StringVar(&cfg.templates, "templates", "", "comma separated text/template files redefining the blocks of the generated code")
+	fset.BoolVar(&cfg.force, "force", false, "regenerate the wrappers even if their input has not changed")
+	fset.BoolVar(&cfg.diff, "diff", false, "print a unified diff of the changes instead of writing files")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if cfg.typeName == "" {
+		return fmt.Errorf("-type is required")
+	}
+	if constructors == "" {
+		return fmt.Errorf("-constructors is required")
+	}
+	if cfg.constructor == "" {
+		return fmt.Errorf("-constructor is required, naming the exported constructor generated with the options")
+	}
+	cfg.mode = optiongen.ModeOptions
+	dir := "."
+	if fset.NArg() > 0 {
+		dir = fset.Arg(0)
+	}
+
+	s, err := load(cfg, dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, name := range strings.Split(constructors, ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	if err := optiongen.FindLegacy(dir, s, names...); err != nil {
+		return err
+	}
+
+	output := cfg.output
+	if output == "" {
+		output = filepath.Join(dir, strings.ToLower(cfg.typeName)+"_migrate_gen.go")
+	}
+	if err := cfg.write(output, s, optiongen.ModeMigrate, func() ([]byte, error) {
+		return optiongen.GenerateMigration(s)
+	}); err != nil {
+		return err
+	}
+
+	stripped := map[string]bool{}
+	for _, l := range s.Legacy {
+		if stripped[l.File] || filepath.Clean(l.File) == filepath.Clean(output) {
+			continue
+		}
+		stripped[l.File] = true
+		old, err := os.ReadFile(l.File)
+		if err != nil {
+			return err
+		}
+		src, err := optiongen.StripLegacy(l.File, old, s.Legacy)
+		if err != nil {
+			return fmt.Errorf("%s: %w", l.File, err)
+		}
+		if err := cfg.store(l.File, old, src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const legacySource = `package app
+
+type Server struct {
+	host string
+	port int
+}
+
+// NewWithHostAndPort creates a server listening on host and port.
+func NewWithHostAndPort(host string, port int) (*Server, error) {
+	return &Server{host: host, port: port}, nil
+}
+
+// NewWithHostAndDefaults sets up more than its parameters.
+func NewWithHostAndDefaults(host string) (*Server, error) {
+	return &Server{host: host, port: 80}, nil
+}
+
+// NewLocal returns no error.
+func NewLocal(port int) *Server {
+	return &Server{port: port}
+}
+
+func keep() {}
+`
+
+func TestRunMigrate(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "server.go")
+	if err := os.WriteFile(source, []byte(legacySource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runMigrate([]string{"-type=Server", "-constructor=NewServer", "-constructors=NewWithHostAndPort", dir}); err != nil {
+		t.Fatalf("runMigrate() error = %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "server_migrate_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// NewWithHostAndPort creates a server listening on host and port.\n//\n// Deprecated: use NewServer with WithHost and WithPort.\n",
+		"func NewWithHostAndPort(host string, port int) (*Server, error) {",
+		"return NewServer(\n\t\tWithHost(host),\n\t\tWithPort(port),\n\t)",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("server_migrate_gen.go does not contain %q:\n%s", want, got)
+		}
+	}
+	stripped, err := os.ReadFile(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(stripped), "NewWithHostAndPort(") || !strings.Contains(string(stripped), "func keep() {}") {
+		t.Errorf("server.go after the migration:\n%s\nwant only the constructor removed", stripped)
+	}
+
+	// The wrappers are found again on a second run.
+	if err := runMigrate([]string{"-type=Server", "-constructor=NewServer", "-constructors=NewWithHostAndPort", "-force", dir}); err != nil {
+		t.Errorf("runMigrate() again error = %v", err)
+	}
+}
+
+func TestRunMigrateErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "server.go"), []byte(legacySource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"-constructors=NewWithHostAndPort", dir},
+		{"-type=Server", dir},
+		{"-type=Server", "-constructors=NewWithHostAndPort", dir},
+	} {
+		if err := runMigrate(args); err == nil {
+			t.Errorf("runMigrate(%q) succeeded", args)
+		}
+	}
+	for constructor, want := range map[string]string{
+		"NewMissing":             "NewMissing",
+		"NewWithHostAndDefaults": "it sets more than its parameters (port: 80)",
+		"NewLocal":               "want results (*Server, error)",
+	} {
+		err := runMigrate([]string{"-type=Server", "-constructor=NewServer", "-constructors=" + constructor, dir})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("runMigrate(%s) error = %v, want it to mention %q", constructor, err, want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/StevenCyb/golang-functional-options/optiongen"
+)
+
+// projectFile is the project configuration used when neither -type nor
+// -config is set.
+const projectFile = ".optiongen.yaml"
+
+// project is the parsed project configuration, listing the structs of a
+// project in place of a go:generate directive per struct:
+//
+//	templates: [house.tmpl]
+//	targets:
+//	  - type: Client
+//	    dir: ./client
+//	    modes: [options, builder]
+//	    env-prefix: MYAPP
+//	    exclude: [baseClient]
+//	    rename: {header: Headers}
+//
+// Paths are relative to the configuration file. The templates apply to all
+// targets in front of their own.
+type project struct {
+	Templates []string `yaml:"templates"`
+	Targets   []target `yaml:"targets"`
+}
+
+// target configures the generation for one struct, mirroring the command
+// line flags.
+type target struct {
+	Type          string               `yaml:"type"`
+	Dir           string               `yaml:"dir"`
+	Modes         []optiongen.Mode     `yaml:"modes"`
+	Output        string               `yaml:"output"`
+	Doc           string               `yaml:"doc"`
+	Constructor   string               `yaml:"constructor"`
+	WithTests     bool                 `yaml:"with-tests"`
+	WithFuzz      bool                 `yaml:"with-fuzz"`
+	Pkg           string               `yaml:"pkg"`
+	Templates     []string             `yaml:"templates"`
+	EnvPrefix     string               `yaml:"env-prefix"`
+	Flags         bool                 `yaml:"flags"`
+	PFlag         bool                 `yaml:"pflag"`
+	Loaders       []string             `yaml:"loaders"`
+	BridgeSetters bool                 `yaml:"bridge-setters"`
+	Plugins       []string             `yaml:"plugins"`
+	Prefix        string               `yaml:"prefix"`
+	Style         optiongen.Style      `yaml:"style"`
+	Unexported    optiongen.Unexported `yaml:"unexported"`
+	Exclude       []string             `yaml:"exclude"`
+	Rename        map[string]string    `yaml:"rename"`
+}
+
+// job is a run of the generator for one mode of a target.
+type job struct {
+	cfg config
+	dir string
+}
+
+// loadProject reads the project configuration at path and translates its
+// targets into jobs, which inherit -force, -diff and -watch from base.
+func loadProject(path string, base config) ([]job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p project
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	root := filepath.Dir(path)
+	var jobs []job
+	for i, t := range p.Targets {
+		if t.Type == "" {
+			return nil, fmt.Errorf("%s: target %d has no type", path, i+1)
+		}
+		modes := t.Modes
+		if len(modes) == 0 {
+			modes = []optiongen.Mode{optiongen.ModeOptions}
+		}
+		if t.Output != "" && len(modes) > 1 {
+			return nil, fmt.Errorf("%s: target %s sets an output for several modes", path, t.Type)
+		}
+		var templates []string
+		for _, tmpl := range slices.Concat(p.Templates, t.Templates) {
+			templates = append(templates, filepath.Join(root, tmpl))
+		}
+		for _, mode := range modes {
+			cfg := config{
+				typeName:    t.Type,
+				mode:        mode,
+				doc:         t.Doc,
+				constructor: t.Constructor,
+				pkg:         t.Pkg,
+				templates:   strings.Join(templates, ","),
+				force:       base.force,
+				diff:        base.diff,
+				prefix:      t.Prefix,
+				style:       t.Style,
+				unexported:  t.Unexported,
+				exclude:     t.Exclude,
+				rename:      t.Rename,
+			}
+			if t.Output != "" {
+				cfg.output = filepath.Join(root, t.Output)
+			}
+			// The additional output builds on the options mode.
+			if mode == optiongen.ModeOptions {
+				cfg.withTests = t.WithTests
+				cfg.withFuzz = t.WithFuzz
+				cfg.envPrefix = t.EnvPrefix
+				cfg.flags = t.Flags
+				cfg.pflag = t.PFlag
+				cfg.loaders = strings.Join(t.Loaders, ",")
+				cfg.bridge = t.BridgeSetters
+				cfg.plugins = strings.Join(t.Plugins, ",")
+			}
+			jobs = append(jobs, job{cfg: cfg, dir: filepath.Join(root, t.Dir)})
+		}
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("%s: no targets", path)
+	}
+	return jobs, nil
+}
+
+// customize applies the exclusions and renames of cfg to the fields of s.
+func customize(s *optiongen.Struct, cfg config) error {
+	for _, name := range cfg.exclude {
+		if err := s.Exclude(name); err != nil {
+			return err
+		}
+	}
+	names := make([]string, 0, len(cfg.rename))
+	for name := range cfg.rename {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := s.Rename(name, cfg.rename[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
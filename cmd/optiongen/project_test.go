@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/optiongen"
+)
+
+func TestLoadProject(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, projectFile)
+	err := os.WriteFile(path, []byte(`templates: [house.tmpl]
+targets:
+  - type: Client
+    dir: ./client
+    modes: [options, builder]
+    env-prefix: MYAPP
+    exclude: [baseClient]
+  - type: Server
+    output: server_gen.go
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := loadProject(path, config{force: true})
+	if err != nil {
+		t.Fatalf("loadProject() error = %v", err)
+	}
+	templates := filepath.Join(dir, "house.tmpl")
+	want := []job{
+		{cfg: config{typeName: "Client", mode: optiongen.ModeOptions, templates: templates, force: true, envPrefix: "MYAPP", exclude: []string{"baseClient"}}, dir: filepath.Join(dir, "client")},
+		{cfg: config{typeName: "Client", mode: optiongen.ModeBuilder, templates: templates, force: true, exclude: []string{"baseClient"}}, dir: filepath.Join(dir, "client")},
+		{cfg: config{typeName: "Server", mode: optiongen.ModeOptions, templates: templates, force: true, output: filepath.Join(dir, "server_gen.go")}, dir: dir},
+	}
+	if !reflect.DeepEqual(jobs, want) {
+		t.Errorf("loadProject() = %+v, want %+v", jobs, want)
+	}
+}
+
+func TestLoadProjectErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no targets", content: "targets: []\n", wantErr: "no targets"},
+		{name: "no type", content: "targets:\n  - dir: x\n", wantErr: "target 1 has no type"},
+		{name: "output for several modes", content: "targets:\n  - type: A\n    output: a.go\n    modes: [options, config]\n", wantErr: "target A sets an output for several modes"},
+		{name: "unknown field", content: "targets:\n  - type: A\n    colour: red\n", wantErr: "yaml: unmarshal errors:\n  line 3: field colour not found in type main.target"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), projectFile)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadProject(path, config{})
+			if want := path + ": " + tt.wantErr; err == nil || err.Error() != want {
+				t.Errorf("loadProject() error = %v, want %s", err, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/optiongen"
+)
+
+// runProto implements the proto subcommand: it writes the Go structs of the
+// messages of .proto files to <file>_proto_gen.go in -out and generates
+// options for the top-level messages, or those of -messages, like -type
+// does for a handwritten struct. The structs get accessors and the
+// options an exported constructor per message, so the package can be used
+// from others. The options and enum types are prefixed with the name of the
+// message, such as WithServerBaseURL and ServerFormat, so several messages
+// share the package; names declared twice all the same are reported as
+// errors. The fields of a oneof are mutually exclusive options:
+//
+//	optiongen proto -out ./config -with-tests config.proto
+func runProto(args []string) error {
+	var cfg config
+	var out, pkg, messages, constructor string
+	fset := flag.NewFlagSet("optiongen proto", flag.ExitOnError)
+	fset.StringVar(&out, "out", ".", "directory of the package the structs and options are written to")
+	fset.StringVar(&pkg, "package", "", "name of the package, defaults to the name from go_package or the proto package")
+	fset.StringVar(&messages, "messages", "", "comma separated messages to generate options for, defaults to the top-level messages")
+	fset.StringVar(&constructor, "constructor", "New", `prefix of the generated constructors, named <prefix><Message>, "-" disables them`)
+	fset.StringVar(&cfg.prefix, "prefix", "", "prefix of the generated options in place of With")
+	fset.StringVar((*string)(&cfg.style), "style", "", "casing of the generated options, camel or go")
+	fset.BoolVar(&cfg.withTests, "with-tests", false, "also generate tests for the options")
+	fset.BoolVar(&cfg.withFuzz, "with-fuzz", false, "also generate fuzz targets for the options")
+	fset.BoolVar(&cfg.force, "force", false, "regenerate files even if their input has not changed")
+	fset.StringVar(&cfg.envPrefix, "env-prefix", "", "also generate <Message>FromEnv reading the options from environment variables with this prefix")
+	fset.BoolVar(&cfg.flags, "flags", false, "also generate <Message>Flags defining a flag.FlagSet flag per option")
+	fset.BoolVar(&cfg.pflag, "pflag", false, "with -flags, define the flags on a github.com/spf13/pflag FlagSet")
+	fset.StringVar(&cfg.loaders, "loaders", "", "comma separated document formats to generate loaders for, json or yaml")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if fset.NArg() == 0 {
+		return fmt.Errorf("proto: no .proto files given")
+	}
+	cfg.mode = optiongen.ModeOptions
+
+	var jobs []job
+	structs := map[string][]byte{}
+	for _, path := range fset.Args() {
+		file, err := optiongen.ParseProto(path)
+		if err != nil {
+			return err
+		}
+		name := pkg
+		if name == "" {
+			name = file.PackageName()
+		}
+		src, err := optiongen.GenerateProto(file, name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		output := filepath.Join(out, strings.TrimSuffix(file.Name, ".proto")+"_proto_gen.go")
+		old, err := os.ReadFile(output)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if err := cfg.store(output, old, src); err != nil {
+			return err
+		}
+		structs[file.Name] = src
+
+		for _, m := range file.Messages {
+			selected := !m.Nested
+			if messages != "" {
+				selected = false
+				for _, name := range strings.Split(messages, ",") {
+					selected = selected || strings.TrimSpace(name) == m.FullName
+				}
+			}
+			if selected {
+				j := job{cfg: cfg, dir: out}
+				j.cfg.typeName = optiongen.ProtoTypeName(m.FullName)
+				// One name for all messages would collide, so the flag is a
+				// prefix, giving exported constructors such as NewServer by
+				// default.
+				j.cfg.constructor = constructor
+				if constructor != "-" {
+					j.cfg.constructor = constructor + j.cfg.typeName
+				}
+				j.cfg.prefix = cmp.Or(cfg.prefix, "With") + j.cfg.typeName
+				j.cfg.enumPrefix = j.cfg.typeName
+				jobs = append(jobs, j)
+			}
+		}
+	}
+	if len(jobs) == 0 {
+		return fmt.Errorf("proto: no messages matching %s", messages)
+	}
+	if err := collisions(jobs, structs); err != nil {
+		return err
+	}
+	return runAll(jobs)
+}
+
+// collisions reports the names declared twice in the package by the
+// structs of the .proto files, keyed by file name, and the options of the
+// jobs, such as WithServerRetryMode for the retry_mode field of Server and
+// the mode field of a message ServerRetry.
+func collisions(jobs []job, structs map[string][]byte) error {
+	declared := map[string]string{}
+	var errs []error
+	add := func(source string, src []byte) error {
+		names, err := declarations(src)
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		for _, name := range names {
+			if other, ok := declared[name]; ok && other != source {
+				errs = append(errs, fmt.Errorf("proto: %s is declared by %s and %s", name, other, source))
+				continue
+			}
+			declared[name] = source
+		}
+		return nil
+	}
+	files := make([]string, 0, len(structs))
+	for name := range structs {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	for _, name := range files {
+		if err := add(name, structs[name]); err != nil {
+			return err
+		}
+	}
+	for _, j := range jobs {
+		s, err := load(j.cfg, j.dir)
+		if err != nil {
+			return err
+		}
+		src, err := optiongen.Generate(s, optiongen.ModeOptions)
+		if err != nil {
+			return err
+		}
+		if err := add("the options of "+s.Name, src); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// declarations returns the names of the package level declarations of the
+// Go source src, leaving out methods.
+func declarations(src []byte) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names = append(names, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.Name != "_" {
+							names = append(names, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const serverProto = `syntax = "proto3";
+
+package app;
+
+import "google/protobuf/duration.proto";
+
+message Server {
+  string base_url = 1;
+  google.protobuf.Duration timeout = 2;
+  Format format = 3;
+  Retry retry = 4;
+
+  message Retry {
+    int32 max_attempts = 1;
+  }
+}
+
+enum Format {
+  FORMAT_UNSPECIFIED = 0;
+  FORMAT_JSON = 1;
+}
+`
+
+func TestRunProto(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.proto")
+	if err := os.WriteFile(path, []byte(serverProto), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runProto([]string{"-out", dir, path}); err != nil {
+		t.Fatalf("runProto() error = %v", err)
+	}
+
+	want := map[string][]string{
+		"server_proto_gen.go":   {"package app", "type Server struct", "baseURL string", "timeout time.Duration", "type ServerRetry struct"},
+		"server_options_gen.go": {"func WithServerBaseURL(baseURL string)", "func WithServerTimeout(timeout time.Duration)", "type ServerFormat string"},
+	}
+	for file, snippets := range want {
+		got, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range snippets {
+			if !strings.Contains(string(got), s) {
+				t.Errorf("%s does not contain %q:\n%s", file, s, got)
+			}
+		}
+	}
+}
+
+const sharedProto = `syntax = "proto3";
+
+package app;
+
+message Server {
+  string name = 1;
+  Mode mode = 2;
+}
+
+message Client {
+  string name = 1;
+  Mode mode = 2;
+}
+
+enum Mode {
+  MODE_UNSPECIFIED = 0;
+  MODE_FAST = 1;
+}
+`
+
+func TestRunProtoSharedNames(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shared.proto")
+	if err := os.WriteFile(path, []byte(sharedProto), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runProto([]string{"-out", dir, path}); err != nil {
+		t.Fatalf("runProto() error = %v", err)
+	}
+
+	want := map[string][]string{
+		"server_options_gen.go": {"func WithServerName(name string)", "type ServerMode string", "mode != ServerModeUnspecified && mode != ServerModeFast"},
+		"client_options_gen.go": {"func WithClientName(name string)", "type ClientMode string", "mode != ClientModeUnspecified && mode != ClientModeFast"},
+	}
+	for file, snippets := range want {
+		got, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range snippets {
+			if !strings.Contains(string(got), s) {
+				t.Errorf("%s does not contain %q:\n%s", file, s, got)
+			}
+		}
+	}
+}
+
+func TestRunProtoCollisions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "collide.proto")
+	src := `syntax = "proto3";
+
+package app;
+
+message Server {
+  string retry_mode = 1;
+}
+
+message ServerRetry {
+  string mode = 1;
+}
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := runProto([]string{"-out", dir, path})
+	if want := "proto: WithServerRetryMode is declared by the options of Server and the options of ServerRetry"; err == nil || err.Error() != want {
+		t.Errorf("runProto() error = %v, want %s", err, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "server_options_gen.go")); err == nil {
+		t.Error("runProto() wrote options despite the collision")
+	}
+}
+
+func TestRunProtoNoFiles(t *testing.T) {
+	if err := runProto(nil); err == nil || err.Error() != "proto: no .proto files given" {
+		t.Errorf("runProto() error = %v, want no .proto files given", err)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/StevenCyb/golang-functional-options/optiongen"
+)
+
+// runReport implements the report subcommand, printing the coverage of the
+// structs selected like for generation by options, defaults and tests:
+//
+//	optiongen report -type=Client
+//	optiongen report ./...
+//
+// With -strict it fails when a field lacks a declared or tested option.
+func runReport(args []string, w io.Writer) error {
+	var cfg config
+	var strict bool
+	fset := flag.NewFlagSet("optiongen report", flag.ExitOnError)
+	fset.StringVar(&cfg.typeName, "type", "", "name of the struct to report on")
+	fset.StringVar(&cfg.pkg, "pkg", "", "sub-package the options are generated into")
+	fset.StringVar(&cfg.prefix, "prefix", "", "prefix of the generated options in place of With")
+	fset.StringVar((*string)(&cfg.style), "style", "", "casing of the generated options, camel or go")
+	fset.StringVar(&cfg.templates, "templates", "", "comma separated text/template files redefining the blocks of the generated code")
+	fset.StringVar(&cfg.project, "config", "", "project configuration listing the structs, defaults to "+projectFile+" when -type is not set")
+	fset.BoolVar(&strict, "strict", false, "fail when a field lacks a declared or tested option")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	cfg.mode = optiongen.ModeOptions
+
+	jobs, err := plan(&cfg, fset.Args())
+	if err != nil {
+		return err
+	}
+	var gaps int
+	seen := map[string]bool{}
+	for _, j := range jobs {
+		// Project targets yield a job per mode.
+		key := j.dir + "\x00" + j.cfg.typeName
+		if j.cfg.mode != optiongen.ModeOptions || seen[key] {
+			continue
+		}
+		if len(seen) > 0 {
+			fmt.Fprintln(w)
+		}
+		seen[key] = true
+		if j.cfg.typeName == "" {
+			return fmt.Errorf("-type is required")
+		}
+		s, err := load(j.cfg, j.dir)
+		if err != nil {
+			return fmt.Errorf("%s: %w", j.cfg.typeName, err)
+		}
+		dirs := []string{j.dir}
+		if j.cfg.pkg != "" {
+			dirs = append(dirs, filepath.Join(j.dir, j.cfg.pkg))
+		}
+		c, err := optiongen.Cover(s, dirs...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", j.cfg.typeName, err)
+		}
+		gaps += printCoverage(w, filepath.ToSlash(j.dir), c)
+	}
+	if strict && gaps > 0 {
+		return fmt.Errorf("%d fields lack a declared or tested option", gaps)
+	}
+	return nil
+}
+
+// printCoverage prints c as a table and returns the number of fields with
+// an option that is not declared or not tested:
+//
+//	example/optiongen Client
+//	FIELD              OPTION                DECLARED  DEFAULT  TESTED
+//	baseURL            WithBaseURL           yes       -        no
+//	retry.maxAttempts  WithRetryMaxAttempts  yes       3        no
+//	baseClient         -                     -         -        -
+//	2 of 3 fields with options, 1 with defaults, 0 tested
+func printCoverage(w io.Writer, dir string, c *optiongen.Coverage) int {
+	fmt.Fprintf(w, "%s %s\n", dir, c.Struct)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tOPTION\tDECLARED\tDEFAULT\tTESTED")
+	gaps := 0
+	for _, f := range c.Fields {
+		if f.Option == "" {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\n", f.Path)
+			continue
+		}
+		if !f.Declared || !f.Tested {
+			gaps++
+		}
+		def := f.Default
+		if def == "" {
+			def = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", f.Path, f.Option, yesNo(f.Declared), def, yesNo(f.Tested))
+	}
+	tw.Flush()
+	total, declared, defaults, tested := c.Counts()
+	fmt.Fprintf(w, "%d of %d fields with options, %d with defaults, %d tested\n", declared, total, defaults, tested)
+	return gaps
+}
+
+func yesNo(ok bool) string {
+	return map[bool]string{true: "yes", false: "no"}[ok]
+}
+
+// subcommands are the commands selected by the first argument.
+var subcommands = map[string]func(args []string) error{
+	"migrate": runMigrate,
+	"proto":   runProto,
+	"report":  func(args []string) error { return runReport(args, os.Stdout) },
+}
+
+// arg returns args[i], or an empty string if there are fewer arguments.
+func arg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunReport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"server.go":       serverSource,
+		"options.go":      "package app\n\nfunc WithHost(host string) {}\n",
+		"options_test.go": "package app\n\nvar _ = WithHost\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out strings.Builder
+	if err := runReport([]string{"-type=Server", dir}, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.ToSlash(dir) + ` Server
+FIELD  OPTION    DECLARED  DEFAULT  TESTED
+host   WithHost  yes       -        yes
+port   WithPort  no        -        no
+1 of 2 fields with options, 0 with defaults, 1 tested
+`
+	if out.String() != want {
+		t.Errorf("runReport() printed\n%s\nwant\n%s", out.String(), want)
+	}
+
+	err := runReport([]string{"-type=Server", "-strict", dir}, &out)
+	if err == nil || err.Error() != "1 fields lack a declared or tested option" {
+		t.Errorf("runReport(-strict) error = %v, want the number of gaps", err)
+	}
+}
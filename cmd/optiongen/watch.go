@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/internal/snapshot"
+)
+
+// pollInterval is how often -watch checks the sources for changes.
+const pollInterval = 500 * time.Millisecond
+
+// watch calls generate whenever a Go source file in dirs or one of extra
+// changes, printing its errors instead of giving up, so a struct caught
+// mid-edit does not end the session. Generated files are ignored, as
+// writing them would trigger the next run. It only returns when a directory
+// cannot be read.
+func watch(dirs, extra []string, generate func() error) error {
+	paths, err := watched(dirs, extra)
+	if err != nil {
+		return err
+	}
+	last := snapshot.Files(paths)
+	for {
+		time.Sleep(pollInterval)
+		// Files created in dirs count as changes as well.
+		if paths, err = watched(dirs, extra); err != nil {
+			return err
+		}
+		current := snapshot.Files(paths)
+		if current == last {
+			continue
+		}
+		last = current
+		if err := generate(); err != nil {
+			fmt.Fprintln(os.Stderr, "optiongen:", err)
+			continue
+		}
+		fmt.Fprintln(os.Stderr, "optiongen: regenerated", time.Now().Format(time.TimeOnly))
+	}
+}
+
+// watched returns the paths of the files watched in dirs, next to extra.
+func watched(dirs, extra []string) ([]string, error) {
+	paths := slices.Clone(extra)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_gen.go") || strings.HasSuffix(name, "_gen_test.go") {
+				continue
+			}
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type ILogger interface{}
+
+type Client struct {
+	baseURL    string
+	header     map[string]string
+	logger     ILogger
+	baseClient *http.Client
+}
+
+func New(baseURL string, opts ...options.Option[Client]) *Client {
+	return options.Apply(&Client{
+		baseURL:    baseURL,
+		header:     map[string]string{},
+		baseClient: &http.Client{},
+	}, opts...)
+}
+
+func WithHeader(header map[string]string) options.Option[Client] {
+	return func(c *Client) {
+		c.header = header
+	}
+}
+
+func WithLogger(logger ILogger) options.Option[Client] {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+func main() {
+	client := New("https://api.example.com",
+		WithHeader(map[string]string{"Authorization": "Bearer token"}),
+		WithLogger(nil),
+	)
+
+	fmt.Printf("Client: %+v\n", client)
+}
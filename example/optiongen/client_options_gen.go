@@ -0,0 +1,150 @@
+// Code generated by optiongen. DO NOT EDIT.
+// optiongen:fingerprint 9399d3d8def9f91f52985927b933adcb
+
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// WithBaseURL sets the baseURL field of Client.
+//
+// baseURL is the URL all requests are resolved against.
+func WithBaseURL(baseURL string) options.OptionE[Client] {
+	return options.NamedE("WithBaseURL", baseURL, func(c *Client) error {
+		if u, err := url.Parse(baseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("baseURL must be an absolute URL, got %q", baseURL)
+		}
+		c.baseURL = baseURL
+		return nil
+	})
+}
+
+// WithHeaders sets the header field of Client.
+//
+// header is sent with every request.
+func WithHeaders(header map[string]string) options.OptionE[Client] {
+	return options.NamedE("WithHeaders", header, func(c *Client) error {
+		c.header = header
+		return nil
+	})
+}
+
+// WithHeader sets the entry key of the header field of Client to value,
+// keeping the other entries, unlike WithHeaders.
+func WithHeader(key string, value string) options.OptionE[Client] {
+	return options.NamedE("WithHeader", map[string]string{key: value}, func(c *Client) error {
+		header := make(map[string]string, len(c.header)+1)
+		maps.Copy(header, c.header)
+		header[key] = value
+		c.header = header
+		return nil
+	})
+}
+
+// WithLogger sets the logger field of Client.
+func WithLogger(logger ILogger) options.OptionE[Client] {
+	return options.NamedE("WithLogger", logger, func(c *Client) error {
+		c.logger = logger
+		return nil
+	})
+}
+
+// WithRetry sets the retry field of Client.
+//
+// retry controls how failed requests are repeated.
+func WithRetry(retry Retry) options.OptionE[Client] {
+	return options.NamedE("WithRetry", retry, func(c *Client) error {
+		c.retry = retry
+		return nil
+	})
+}
+
+// WithBaseClient sets the baseClient field of Client.
+//
+// baseClient sends the requests.
+func WithBaseClient(baseClient *http.Client) options.OptionE[Client] {
+	return options.NamedE("WithBaseClient", baseClient, func(c *Client) error {
+		c.baseClient = baseClient
+		return nil
+	})
+}
+
+// WithRetryMaxAttempts sets the maxAttempts field of the Retry in Client.
+//
+// maxAttempts is the number of attempts including the first one.
+func WithRetryMaxAttempts(maxAttempts int) options.OptionE[Client] {
+	return options.NamedE("WithRetryMaxAttempts", maxAttempts, options.SubE(
+		func(c *Client) *Retry { return &c.retry },
+		func(r *Retry) error {
+			if maxAttempts < 1 {
+				return fmt.Errorf("maxAttempts must be at least 1, got %v", maxAttempts)
+			}
+			r.maxAttempts = maxAttempts
+			return nil
+		},
+	))
+}
+
+// WithRetryBackoff sets the backoff field of the Retry in Client.
+//
+// backoff is the delay between attempts.
+func WithRetryBackoff(backoff time.Duration) options.OptionE[Client] {
+	return options.NamedE("WithRetryBackoff", backoff, options.SubE(
+		func(c *Client) *Retry { return &c.retry },
+		func(r *Retry) error {
+			r.backoff = backoff
+			return nil
+		},
+	))
+}
+
+// WithRetryBackoffString is WithRetryBackoff taking a string such as "30s",
+// parsed by options.ParseDuration.
+func WithRetryBackoffString(backoff string) options.OptionE[Client] {
+	return options.NamedE("WithRetryBackoffString", backoff, func(c *Client) error {
+		v, err := options.ParseDuration(backoff)
+		if err != nil {
+			return err
+		}
+		return WithRetryBackoff(v)(c)
+	})
+}
+
+// clientDefaults returns the options setting the default values declared
+// by the default tags of Client.
+func clientDefaults() []options.OptionE[Client] {
+	return []options.OptionE[Client]{
+		WithBaseClient(newDefaultHTTPClient()),
+		WithRetryMaxAttempts(3),
+		WithRetryBackoff(100 * time.Millisecond),
+	}
+}
+
+// newClient applies opts to a new Client.
+//
+// The defaults of clientDefaults are set beforehand and do not count as
+// supplied options.
+//
+// Required options: WithBaseURL.
+func newClient(opts ...options.OptionE[Client]) (*Client, error) {
+	c := new(Client)
+	for _, opt := range clientDefaults() {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	opts = append([]options.OptionE[Client]{
+		options.RequiredOption[Client]("WithBaseURL"),
+	}, opts...)
+	if err := options.ApplyE(c, opts...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,27 @@
+// Code generated by optiongen. DO NOT EDIT.
+// optiongen:fingerprint 64a5360939242a175a284e15adf266f6
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// String formats the fields of c that options are generated for like
+// %+v, masking the sensitive ones.
+func (c Client) String() string {
+	return fmt.Sprintf("{baseURL:%+v header:*** logger:%+v retry:%+v baseClient:%+v}", c.baseURL, c.logger, c.retry, c.baseClient)
+}
+
+// LogValue implements slog.LogValuer like String, so c can be logged
+// without leaking the sensitive fields.
+func (c Client) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("baseURL", c.baseURL),
+		slog.String("header", "***"),
+		slog.Any("logger", c.logger),
+		slog.Any("retry", c.retry),
+		slog.Any("baseClient", c.baseClient),
+	)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+//go:generate go run ../../cmd/optiongen -type=Client
+
+type ILogger interface{}
+
+// Client is also picked up by running optiongen with ./... from the root of
+// the repository.
+//
+//optiongen:options
+type Client struct {
+	// baseURL is the URL all requests are resolved against.
+	baseURL string `option:"required" validate:"url"`
+	// header is sent with every request.
+	header map[string]string `option:"name=Headers,sensitive"`
+	logger ILogger
+	// retry controls how failed requests are repeated.
+	retry Retry
+	// baseClient sends the requests.
+	baseClient *http.Client `default:"func=newDefaultHTTPClient"`
+}
+
+type Retry struct {
+	// maxAttempts is the number of attempts including the first one.
+	maxAttempts int `validate:"min=1" default:"3"`
+	// backoff is the delay between attempts.
+	backoff time.Duration `default:"100ms"`
+}
+
+func New(opts ...options.OptionE[Client]) (*Client, error) {
+	client, err := newClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if client.header == nil {
+		client.header = map[string]string{}
+	}
+	return client, nil
+}
+
+func newDefaultHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+func main() {
+	client, err := New(
+		WithBaseURL("https://api.example.com"),
+		WithHeader("Authorization", "Bearer token"),
+		WithLogger(nil),
+		WithRetryMaxAttempts(5),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Client: %+v\n", client)
+}
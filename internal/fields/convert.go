@@ -0,0 +1,222 @@
+package fields
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// TextUnmarshaler reports whether strings are converted to values of t by
+// an UnmarshalText method, of t or of a pointer to it.
+func TextUnmarshaler(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType) ||
+		t.Kind() == reflect.Pointer && t.Implements(textUnmarshalerType)
+}
+
+// Convert converts v into a value of type t. Besides assignable and
+// convertible values it understands strings for types implementing
+// encoding.TextUnmarshaler and scalar types, including durations, comma
+// separated strings for slices, JSON style numbers, []any for slices and
+// map[string]any for maps.
+func Convert(v any, t reflect.Type) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Zero(t), nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Type().AssignableTo(t) {
+		out := reflect.New(t).Elem()
+		out.Set(rv)
+		return out, nil
+	}
+	if s, ok := v.(string); ok {
+		return parse(s, t)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := Convert(v, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(elem)
+		return p, nil
+	case reflect.Slice:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			break
+		}
+		out := reflect.MakeSlice(t, rv.Len(), rv.Len())
+		for i := range rv.Len() {
+			elem, err := Convert(rv.Index(i).Interface(), t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("index %d: %w", i, err)
+			}
+			out.Index(i).Set(elem)
+		}
+		return out, nil
+	case reflect.Map:
+		if rv.Kind() != reflect.Map {
+			break
+		}
+		out := reflect.MakeMapWithSize(t, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := Convert(iter.Key().Interface(), t.Key())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %v: %w", iter.Key(), err)
+			}
+			elem, err := Convert(iter.Value().Interface(), t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %v: %w", iter.Key(), err)
+			}
+			out.SetMapIndex(key, elem)
+		}
+		return out, nil
+	}
+	if isNumber(rv.Kind()) && isNumber(t.Kind()) {
+		return convertNumber(rv, t)
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %T as %s", v, t)
+}
+
+// convertNumber converts the number rv into a value of the numeric type t,
+// rejecting values t cannot represent instead of letting them wrap around.
+// Decoders such as encoding/json produce float64 for every number, so
+// floats are accepted for integer types when they are whole.
+func convertNumber(rv reflect.Value, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	switch k := rv.Kind(); {
+	case k >= reflect.Int && k <= reflect.Int64:
+		n := rv.Int()
+		switch {
+		case isUnsigned(t.Kind()) && n < 0:
+			return reflect.Value{}, fmt.Errorf("cannot use negative %d as %s", n, t)
+		case isSigned(t.Kind()) && out.OverflowInt(n),
+			isUnsigned(t.Kind()) && out.OverflowUint(uint64(n)):
+			return reflect.Value{}, fmt.Errorf("%d overflows %s", n, t)
+		}
+	case k >= reflect.Uint && k <= reflect.Uintptr:
+		n := rv.Uint()
+		if isSigned(t.Kind()) && (n > math.MaxInt64 || out.OverflowInt(int64(n))) ||
+			isUnsigned(t.Kind()) && out.OverflowUint(n) {
+			return reflect.Value{}, fmt.Errorf("%d overflows %s", n, t)
+		}
+	default:
+		f := rv.Float()
+		switch {
+		case !isSigned(t.Kind()) && !isUnsigned(t.Kind()):
+			if out.OverflowFloat(f) {
+				return reflect.Value{}, fmt.Errorf("%v overflows %s", f, t)
+			}
+		case f != math.Trunc(f):
+			return reflect.Value{}, fmt.Errorf("cannot use %v as %s", f, t)
+		case isUnsigned(t.Kind()) && f < 0:
+			return reflect.Value{}, fmt.Errorf("cannot use negative %v as %s", f, t)
+		case isSigned(t.Kind()) && (f < math.MinInt64 || f >= math.MaxInt64 || out.OverflowInt(int64(f))),
+			isUnsigned(t.Kind()) && (f >= math.MaxUint64 || out.OverflowUint(uint64(f))):
+			return reflect.Value{}, fmt.Errorf("%v overflows %s", f, t)
+		}
+	}
+	out.Set(rv.Convert(t))
+	return out, nil
+}
+
+// parse converts the string s into a value of type t.
+func parse(s string, t reflect.Type) (reflect.Value, error) {
+	if TextUnmarshaler(t) {
+		// Types such as log levels and IP addresses know their text form
+		// better than the kind they are declared with.
+		if t.Kind() == reflect.Pointer && t.Implements(textUnmarshalerType) {
+			p := reflect.New(t.Elem())
+			if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+				return reflect.Value{}, err
+			}
+			return p, nil
+		}
+		p := reflect.New(t)
+		if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return reflect.Value{}, err
+		}
+		return p.Elem(), nil
+	}
+
+	out := reflect.New(t).Elem()
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetInt(int64(d))
+		return out, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		out.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 0, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetFloat(f)
+	case reflect.Slice:
+		if s == "" {
+			return reflect.MakeSlice(t, 0, 0), nil
+		}
+		items := strings.Split(s, ",")
+		list := make([]any, len(items))
+		for i, item := range items {
+			list[i] = strings.TrimSpace(item)
+		}
+		return Convert(list, t)
+	case reflect.Pointer:
+		elem, err := parse(s, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Set(reflect.New(t.Elem()))
+		out.Elem().Set(elem)
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot parse %q as %s", s, t)
+	}
+	return out, nil
+}
+
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+func isSigned(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUnsigned(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
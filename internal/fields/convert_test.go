@@ -0,0 +1,64 @@
+package fields
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		t       reflect.Type
+		want    any
+		wantErr string
+	}{
+		{name: "assignable", v: 5, t: reflect.TypeFor[int](), want: 5},
+		{name: "nil", v: nil, t: reflect.TypeFor[*int](), want: (*int)(nil)},
+		{name: "int to int8", v: 127, t: reflect.TypeFor[int8](), want: int8(127)},
+		{name: "int overflows int8", v: 128, t: reflect.TypeFor[int8](), wantErr: "128 overflows int8"},
+		{name: "int below int8", v: -129, t: reflect.TypeFor[int8](), wantErr: "-129 overflows int8"},
+		{name: "negative to uint", v: -1, t: reflect.TypeFor[uint](), wantErr: "cannot use negative -1 as uint"},
+		{name: "int overflows uint16", v: 1 << 16, t: reflect.TypeFor[uint16](), wantErr: "65536 overflows uint16"},
+		{name: "uint64 to int64", v: uint64(math.MaxInt64), t: reflect.TypeFor[int64](), want: int64(math.MaxInt64)},
+		{name: "uint64 overflows int64", v: uint64(math.MaxUint64), t: reflect.TypeFor[int64](), wantErr: "18446744073709551615 overflows int64"},
+		{name: "whole float to int", v: 3.0, t: reflect.TypeFor[int](), want: 3},
+		{name: "fractional float to int", v: 3.5, t: reflect.TypeFor[int](), wantErr: "cannot use 3.5 as int"},
+		{name: "negative float to uint8", v: -1.0, t: reflect.TypeFor[uint8](), wantErr: "cannot use negative -1 as uint8"},
+		{name: "float overflows uint8", v: 256.0, t: reflect.TypeFor[uint8](), wantErr: "256 overflows uint8"},
+		{name: "float overflows int64", v: 1e19, t: reflect.TypeFor[int64](), wantErr: "1e+19 overflows int64"},
+		{name: "float overflows float32", v: 1e40, t: reflect.TypeFor[float32](), wantErr: "1e+40 overflows float32"},
+		{name: "duration string", v: "1m30s", t: reflect.TypeFor[time.Duration](), want: 90 * time.Second},
+		{name: "string to int", v: "0x10", t: reflect.TypeFor[int](), want: 16},
+		{name: "string overflows int8", v: "300", t: reflect.TypeFor[int8](), wantErr: `strconv.ParseInt: parsing "300": value out of range`},
+		{name: "comma separated", v: "a, b", t: reflect.TypeFor[[]string](), want: []string{"a", "b"}},
+		{name: "slice elements", v: []any{1, 2.0}, t: reflect.TypeFor[[]uint8](), want: []uint8{1, 2}},
+		{name: "slice element overflows", v: []any{1, 300}, t: reflect.TypeFor[[]uint8](), wantErr: "index 1: 300 overflows uint8"},
+		{name: "map", v: map[string]any{"a": 1}, t: reflect.TypeFor[map[string]int64](), want: map[string]int64{"a": 1}},
+		{name: "pointer", v: 1, t: reflect.TypeFor[*int](), want: ptr(1)},
+		{name: "mismatch", v: true, t: reflect.TypeFor[int](), wantErr: "cannot use bool as int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.v, tt.t)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Convert() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.want) {
+				t.Errorf("Convert() = %#v, want %#v", got.Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
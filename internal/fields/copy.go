@@ -0,0 +1,88 @@
+package fields
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Copy returns a deep copy of src. Structs, arrays, slices and maps are
+// copied recursively. Pointers are only followed when they point to a type
+// declared in the same package as T; pointers to foreign types, interfaces,
+// functions and channels are shared with src, so values such as loggers or
+// connection pools are never duplicated. Pointers that lead back to src or
+// to a value copied earlier point to its copy, so cycles are preserved.
+func Copy[T any](src *T) *T {
+	dst := new(T)
+	c := copier{
+		pkg:  reflect.TypeFor[T]().PkgPath(),
+		seen: map[pointer]reflect.Value{{reflect.TypeFor[*T](), uintptr(unsafe.Pointer(src))}: reflect.ValueOf(dst)},
+	}
+	c.copy(reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem())
+	return dst
+}
+
+type copier struct {
+	pkg  string
+	seen map[pointer]reflect.Value
+}
+
+// pointer identifies a copied pointer. The type is part of the key because a
+// struct and its first field share an address.
+type pointer struct {
+	t    reflect.Type
+	addr uintptr
+}
+
+func (c *copier) copy(dst, src reflect.Value) {
+	dst, src = Access(dst), Access(src)
+	switch src.Kind() {
+	case reflect.Struct:
+		for i := range src.NumField() {
+			c.copy(dst.Field(i), src.Field(i))
+		}
+	case reflect.Array:
+		for i := range src.Len() {
+			c.copy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		for i := range src.Len() {
+			c.copy(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			// Map values are not addressable, copy them through a temporary.
+			tmp := reflect.New(src.Type().Elem()).Elem()
+			tmp.Set(iter.Value())
+			v := reflect.New(src.Type().Elem()).Elem()
+			c.copy(v, tmp)
+			m.SetMapIndex(iter.Key(), v)
+		}
+		dst.Set(m)
+	case reflect.Pointer:
+		if src.IsNil() || src.Type().Elem().PkgPath() != c.pkg {
+			dst.Set(src)
+			return
+		}
+		key := pointer{src.Type(), src.Pointer()}
+		if p, ok := c.seen[key]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		c.seen[key] = p
+		c.copy(p.Elem(), src.Elem())
+		dst.Set(p)
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,79 @@
+package fields
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type node struct {
+	name     string
+	tags     []string
+	labels   map[string][]string
+	child    *node
+	client   *http.Client
+	handler  func()
+	children [2]*node
+}
+
+func TestCopy(t *testing.T) {
+	client := &http.Client{}
+	tests := []struct {
+		name string
+		src  *node
+	}{
+		{name: "zero", src: &node{}},
+		{name: "scalars", src: &node{name: "a"}},
+		{name: "collections", src: &node{tags: []string{"x"}, labels: map[string][]string{"k": {"v"}}}},
+		{name: "nested", src: &node{child: &node{name: "b", tags: []string{"y"}}, children: [2]*node{{name: "c"}}}},
+		{name: "foreign pointers", src: &node{client: client, handler: func() {}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Copy(tt.src)
+			if got == tt.src {
+				t.Fatal("Copy() returned src")
+			}
+			if got.name != tt.src.name || !reflect.DeepEqual(got.tags, tt.src.tags) || !reflect.DeepEqual(got.labels, tt.src.labels) {
+				t.Errorf("Copy() = %+v, want %+v", got, tt.src)
+			}
+			if got.client != tt.src.client {
+				t.Error("Copy() duplicated a pointer to a foreign type")
+			}
+			if (got.handler == nil) != (tt.src.handler == nil) {
+				t.Error("Copy() dropped a function")
+			}
+			if len(tt.src.tags) > 0 && &got.tags[0] == &tt.src.tags[0] {
+				t.Error("Copy() shares a slice")
+			}
+			if k, ok := tt.src.labels["k"]; ok && &got.labels["k"][0] == &k[0] {
+				t.Error("Copy() shares a slice held by a map")
+			}
+			if tt.src.child != nil && (got.child == tt.src.child || got.child.name != tt.src.child.name) {
+				t.Errorf("Copy() child = %p %+v, want a copy of %p %+v", got.child, got.child, tt.src.child, tt.src.child)
+			}
+			if tt.src.children[0] != nil && got.children[0] == tt.src.children[0] {
+				t.Error("Copy() shares a pointer held by an array")
+			}
+		})
+	}
+}
+
+type ring struct {
+	head node
+	ref  *node
+	self *ring
+}
+
+func TestCopyCycle(t *testing.T) {
+	src := &ring{head: node{name: "a"}}
+	src.self = src
+	src.ref = &src.head
+	got := Copy(src)
+	if got.self != got {
+		t.Errorf("Copy().self = %p, want the copy %p", got.self, got)
+	}
+	if got.ref == &src.head || got.ref.name != "a" {
+		t.Errorf("Copy().ref = %p %+v, want a copy of %p %+v", got.ref, got.ref, &src.head, src.head)
+	}
+}
@@ -0,0 +1,37 @@
+package fields
+
+import "reflect"
+
+// Difference is a field that holds different values in two structs.
+type Difference struct {
+	Path string
+	A, B any
+}
+
+// Diff compares the addressable struct values a and b field by field. Nested
+// structs declared in the same package as the compared type are descended
+// into and reported with dotted paths, every other field is compared as a
+// whole.
+func Diff(a, b reflect.Value) []Difference {
+	return diff(nil, "", a, b, a.Type().PkgPath())
+}
+
+func diff(out []Difference, prefix string, a, b reflect.Value, pkg string) []Difference {
+	for i := range a.NumField() {
+		f := a.Type().Field(i)
+		path := f.Name
+		if prefix != "" {
+			path = prefix + "." + f.Name
+		}
+		fa, fb := Access(a.Field(i)), Access(b.Field(i))
+		if f.Type.Kind() == reflect.Struct && f.Type.PkgPath() == pkg {
+			out = diff(out, path, fa, fb, pkg)
+			continue
+		}
+		va, vb := fa.Interface(), fb.Interface()
+		if !reflect.DeepEqual(va, vb) {
+			out = append(out, Difference{Path: path, A: va, B: vb})
+		}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package fields
+
+import (
+	"reflect"
+	"testing"
+)
+
+type settings struct {
+	host   string
+	tags   []string
+	retry  retry
+	client *retry
+}
+
+type retry struct {
+	attempts int
+}
+
+func TestDiff(t *testing.T) {
+	shared := &retry{attempts: 1}
+	tests := []struct {
+		name string
+		a, b settings
+		want []Difference
+	}{
+		{name: "equal", a: settings{host: "a", tags: []string{"x"}, client: shared}, b: settings{host: "a", tags: []string{"x"}, client: shared}},
+		{name: "scalar", a: settings{host: "a"}, b: settings{host: "b"}, want: []Difference{{Path: "host", A: "a", B: "b"}}},
+		{name: "slice", a: settings{}, b: settings{tags: []string{"x"}}, want: []Difference{{Path: "tags", A: []string(nil), B: []string{"x"}}}},
+		{name: "nested", a: settings{retry: retry{attempts: 1}}, b: settings{retry: retry{attempts: 3}}, want: []Difference{{Path: "retry.attempts", A: 1, B: 3}}},
+		{
+			name: "pointer as a whole",
+			a:    settings{client: shared},
+			b:    settings{client: &retry{attempts: 2}},
+			want: []Difference{{Path: "client", A: shared, B: &retry{attempts: 2}}},
+		},
+		{
+			name: "in field order",
+			a:    settings{host: "a", retry: retry{attempts: 1}},
+			b:    settings{host: "b", retry: retry{attempts: 2}},
+			want: []Difference{{Path: "host", A: "a", B: "b"}, {Path: "retry.attempts", A: 1, B: 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(reflect.ValueOf(&tt.a).Elem(), reflect.ValueOf(&tt.b).Elem())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Package fields implements the reflection used by the options packages to
+// inspect and modify struct fields, including unexported ones.
+package fields
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Access returns v with read and write access, even when it was reached
+// through an unexported struct field. v must be addressable for that.
+func Access(v reflect.Value) reflect.Value {
+	if v.CanSet() || !v.CanAddr() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// Set assigns value to the field of the struct v reached through path, the
+// indexes of the fields leading to it from v, allocating nil pointers to
+// nested structs on the way. v must be addressable.
+func Set(v reflect.Value, path [][]int, value reflect.Value) {
+	for _, index := range path {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = Access(v.FieldByIndex(index))
+	}
+	v.Set(value)
+}
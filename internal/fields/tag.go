@@ -0,0 +1,127 @@
+package fields
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// TagName is the struct tag read by the options packages.
+const TagName = "option"
+
+// Tag is a parsed `option` struct tag, a comma separated list of flags and
+// name=value items such as `option:"key=url,required"`. The key item sets
+// the key used by the providers. A tag of "-" excludes the field.
+type Tag struct {
+	Key   string
+	Skip  bool
+	Items map[string]string
+}
+
+// ParseTag parses the value of an `option` struct tag. Items without a value
+// are stored with an empty value.
+func ParseTag(tag string) Tag {
+	if tag == "-" {
+		return Tag{Skip: true}
+	}
+	t := Tag{Items: map[string]string{}}
+	for _, item := range strings.Split(tag, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(item, "=")
+		t.Items[name] = value
+	}
+	t.Key = t.Items["key"]
+	return t
+}
+
+// Has reports whether the tag contains the item name.
+func (t Tag) Has(name string) bool {
+	_, ok := t.Items[name]
+	return ok
+}
+
+// Field is a struct field that can be configured through a key.
+type Field struct {
+	reflect.StructField
+	Key string
+	Tag Tag
+}
+
+// Fields returns the configurable fields of the struct type t with the key
+// taken from the `option` tag or derived from the field name, see SnakeCase.
+func Fields(t reflect.Type) []Field {
+	var out []Field
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := ParseTag(f.Tag.Get(TagName))
+		if tag.Skip || f.Name == "_" {
+			continue
+		}
+		key := tag.Key
+		if key == "" {
+			key = SnakeCase(f.Name)
+		}
+		out = append(out, Field{StructField: f, Key: key, Tag: tag})
+	}
+	return out
+}
+
+// Lookup returns the field of available with the given key, matching it
+// exactly first and then ignoring case.
+func Lookup(available []Field, key string) (Field, bool) {
+	for _, f := range available {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	for _, f := range available {
+		if strings.EqualFold(f.Key, key) {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Known returns the entries of m whose keys match a field of the struct
+// type t, see Lookup, descending into the objects of nested structs.
+func Known(t reflect.Type, m map[string]any) map[string]any {
+	available := Fields(t)
+	out := make(map[string]any, len(m))
+	for key, value := range m {
+		f, ok := Lookup(available, key)
+		if !ok {
+			continue
+		}
+		nested := f.Type
+		if nested.Kind() == reflect.Pointer {
+			nested = nested.Elem()
+		}
+		if sub, ok := value.(map[string]any); ok && nested.Kind() == reflect.Struct {
+			value = Known(nested, sub)
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// SnakeCase converts a Go identifier such as baseURL or HTTPClient into
+// base_url and http_client.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			startsWord := i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])))
+			if startsWord {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package fields
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want Tag
+	}{
+		{tag: "", want: Tag{Items: map[string]string{}}},
+		{tag: "-", want: Tag{Skip: true}},
+		{tag: "key=base_url", want: Tag{Key: "base_url", Items: map[string]string{"key": "base_url"}}},
+		{tag: "required, key=timeout ,default=5s", want: Tag{Key: "timeout", Items: map[string]string{"key": "timeout", "required": "", "default": "5s"}}},
+		{tag: "default=5s,required", want: Tag{Items: map[string]string{"default": "5s", "required": ""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := ParseTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTag(%q) = %+v, want %+v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"host":       "host",
+		"baseURL":    "base_url",
+		"HTTPClient": "http_client",
+		"MaxConns":   "max_conns",
+		"ID":         "id",
+	}
+	for name, want := range tests {
+		if got := SnakeCase(name); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	type config struct {
+		BaseURL string
+		timeout int    `option:"key=request_timeout"`
+		secret  string `option:"-"`
+		_       int
+	}
+	var keys []string
+	for _, f := range Fields(reflect.TypeFor[config]()) {
+		keys = append(keys, f.Key)
+	}
+	if want := []string{"base_url", "request_timeout"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Fields() keys = %q, want %q", keys, want)
+	}
+}
@@ -0,0 +1,55 @@
+package fields
+
+import (
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestConvertText(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		t       reflect.Type
+		want    any
+		wantErr string
+	}{
+		{name: "value", v: "10.0.0.1", t: reflect.TypeFor[netip.Addr](), want: netip.MustParseAddr("10.0.0.1")},
+		{name: "slice kind", v: "10.0.0.1", t: reflect.TypeFor[net.IP](), want: net.ParseIP("10.0.0.1")},
+		{name: "pointer", v: "12", t: reflect.TypeFor[*big.Int](), want: big.NewInt(12)},
+		{name: "invalid", v: "x", t: reflect.TypeFor[net.IP](), wantErr: "invalid IP address: x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.v, tt.t)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Convert() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.want) {
+				t.Errorf("Convert() = %#v, want %#v", got.Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTextUnmarshaler(t *testing.T) {
+	for typ, want := range map[reflect.Type]bool{
+		reflect.TypeFor[netip.Addr]():  true,
+		reflect.TypeFor[*big.Int]():    true,
+		reflect.TypeFor[net.IP]():      true,
+		reflect.TypeFor[string]():      false,
+		reflect.TypeFor[*netip.Addr](): true,
+	} {
+		if got := TextUnmarshaler(typ); got != want {
+			t.Errorf("TextUnmarshaler(%s) = %v, want %v", typ, got, want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+// Package snapshot detects changes of files by polling, for the watch modes
+// of the command and the providers.
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Files summarizes the sizes and modification times of the files at paths;
+// it changes whenever one of them is saved.
+func Files(paths []string) string {
+	var b strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			// Files being replaced by an editor count as changed.
+			fmt.Fprintf(&b, "%s missing\n", path)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
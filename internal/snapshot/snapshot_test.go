@@ -0,0 +1,28 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	missing := Files([]string{path})
+	if err := os.WriteFile(path, []byte("a"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	written := Files([]string{path})
+	if written == missing {
+		t.Errorf("Files() = %q for both a missing and a written file", written)
+	}
+	if again := Files([]string{path}); again != written {
+		t.Errorf("Files() = %q, then %q without a change", written, again)
+	}
+	if err := os.WriteFile(path, []byte("ab"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if changed := Files([]string{path}); changed == written {
+		t.Errorf("Files() = %q after the file grew", changed)
+	}
+}
@@ -0,0 +1,98 @@
+// Package optdsl parses a compact description of options, so a whole
+// configuration fits into a single environment variable or flag:
+//
+//	opts, err := optdsl.Parse[Client]("timeout=5s,retries=3,insecure")
+//
+// The description is a comma separated list of options registered with
+// options.Register, each given as name=value. A name alone passes true, for
+// options taking a boolean. Values are converted as for options.Lookup, so
+// durations and numbers are written as usual; values holding commas, such
+// as the elements of a slice, are put in double quotes:
+//
+//	hosts="a.example.com,b.example.com",user-agent="my client"
+package optdsl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// Parse returns the options described by s, in the order they are given.
+// Every unknown option and unconvertible value is reported.
+func Parse[T any](s string) ([]options.Option[T], error) {
+	items, err := split(s)
+	if err != nil {
+		return nil, fmt.Errorf("optdsl: %w", err)
+	}
+	var (
+		opts []options.Option[T]
+		errs []error
+	)
+	for _, item := range items {
+		name, raw, ok := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		var value any = true
+		if ok {
+			raw = strings.TrimSpace(raw)
+			if strings.HasPrefix(raw, `"`) {
+				if raw, err = strconv.Unquote(raw); err != nil {
+					errs = append(errs, fmt.Errorf("optdsl: option %q: malformed quoted value", name))
+					continue
+				}
+			}
+			value = raw
+		}
+		opt, err := options.Lookup[T](name, value)
+		switch {
+		case err != nil && !ok && slices.Contains(options.Registered[T](), name):
+			errs = append(errs, fmt.Errorf("optdsl: option %q needs a value", name))
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("optdsl: %w", err))
+			continue
+		}
+		opts = append(opts, opt)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return opts, nil
+}
+
+// split returns the comma separated items of s, keeping the commas within
+// double quotes. Empty items are dropped.
+func split(s string) ([]string, error) {
+	var (
+		items  []string
+		start  int
+		quoted bool
+	)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && quoted:
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated double quote")
+	}
+	items = append(items, s[start:])
+
+	out := items[:0]
+	for _, item := range items {
+		if strings.TrimSpace(item) != "" {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,73 @@
+package optdsl_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/optdsl"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type client struct {
+	timeout  time.Duration
+	insecure bool
+	hosts    []string
+	agent    string
+}
+
+func init() {
+	options.Register("timeout", func(d time.Duration) options.Option[client] {
+		return func(c *client) { c.timeout = d }
+	})
+	options.Register("insecure", func(b bool) options.Option[client] {
+		return func(c *client) { c.insecure = b }
+	})
+	options.Register("hosts", func(hosts []string) options.Option[client] {
+		return func(c *client) { c.hosts = hosts }
+	})
+	options.Register("user-agent", func(agent string) options.Option[client] {
+		return func(c *client) { c.agent = agent }
+	})
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    client
+		wantErr string
+	}{
+		{name: "empty", s: " , "},
+		{
+			name: "values",
+			s:    `timeout=5s, insecure,hosts="a.example.com,b.example.com",user-agent="my \"client\""`,
+			want: client{timeout: 5 * time.Second, insecure: true, hosts: []string{"a.example.com", "b.example.com"}, agent: `my "client"`},
+		},
+		{name: "false", s: "insecure=false", want: client{}},
+		{name: "unterminated", s: `user-agent="x`, wantErr: "optdsl: unterminated double quote"},
+		{
+			name:    "every error",
+			s:       `retries=3,timeout,user-agent="x"y`,
+			wantErr: "optdsl: unknown option \"retries\"\noptdsl: option \"timeout\" needs a value\noptdsl: option \"user-agent\": malformed quoted value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := optdsl.Parse[client](tt.s)
+			if got := errorString(err); got != tt.wantErr {
+				t.Fatalf("Parse() error = %q, want %q", got, tt.wantErr)
+			}
+			if got := options.Apply(new(client), opts...); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Apply(Parse()) = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
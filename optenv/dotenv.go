@@ -0,0 +1,102 @@
+package optenv
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ParseDotenv reads the variables of a .env file as key=value pairs in the
+// form of os.Environ. Every line holds a variable such as KEY=value,
+// optionally preceded by export. Blank lines and lines starting with # are
+// skipped. Values in double quotes may contain the escapes of Go strings,
+// values in single quotes are taken literally and unquoted values end at a
+// # preceded by a space.
+func ParseDotenv(r io.Reader) ([]string, error) {
+	var (
+		vars []string
+		line int
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: want KEY=value", line)
+		}
+		value, err := dotenvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", line, key, err)
+		}
+		vars = append(vars, key+"="+value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// dotenvValue returns the value of a variable as written after the equals
+// sign.
+func dotenvValue(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		end := closingQuote(s)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated double quote")
+		}
+		return strconv.Unquote(s[:end+1])
+	case strings.HasPrefix(s, "'"):
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single quote")
+		}
+		return s[1 : end+1], nil
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s, nil
+}
+
+// closingQuote returns the index of the double quote ending the quoted
+// string at the start of s, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// readDotenv parses the .env file at path, which may not exist.
+func readDotenv(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("optenv: %w", err)
+	}
+	defer f.Close()
+	vars, err := ParseDotenv(f)
+	if err != nil {
+		return nil, fmt.Errorf("optenv: %s: %w", path, err)
+	}
+	return vars, nil
+}
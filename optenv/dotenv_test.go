@@ -0,0 +1,86 @@
+package optenv_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/optenv"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr string
+	}{
+		{name: "empty", data: "\n# comment\n"},
+		{
+			name: "values",
+			data: "A=1\nexport B = two # comment\nC=\"line\\nbreak # kept\"\nD='$literal \\n'\nE=\n",
+			want: []string{"A=1", "B=two", "C=line\nbreak # kept", `D=$literal \n`, "E="},
+		},
+		{name: "no equals", data: "A=1\nB\n", wantErr: "line 2: want KEY=value"},
+		{name: "space in key", data: "A B=1", wantErr: "line 1: want KEY=value"},
+		{name: "unterminated", data: `A="x`, wantErr: "line 1: A: unterminated double quote"},
+		{name: "unterminated single", data: `A='x`, wantErr: "line 1: A: unterminated single quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := optenv.ParseDotenv(strings.NewReader(tt.data))
+			if got := errorString(err); got != tt.wantErr {
+				t.Fatalf("ParseDotenv() error = %q, want %q", got, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDotenv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvideDotenv(t *testing.T) {
+	dir := t.TempDir()
+	env := filepath.Join(dir, ".env")
+	local := filepath.Join(dir, ".env.local")
+	write := func(path, data string) {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(env, "OPTENV_BASE_URL=http://file\nOPTENV_API_TIMEOUT=1s\n")
+	write(local, "OPTENV_API_TIMEOUT=2s\n")
+	t.Setenv("OPTENV_BASE_URL", "http://env")
+
+	type config struct {
+		BaseURL    string
+		APITimeout string
+	}
+	tests := []struct {
+		name string
+		opts []options.Option[optenv.Config]
+		want config
+	}{
+		{name: "environment first", opts: []options.Option[optenv.Config]{optenv.Dotenv(env, local, filepath.Join(dir, "missing"))}, want: config{BaseURL: "http://env", APITimeout: "2s"}},
+		{name: "override", opts: []options.Option[optenv.Config]{optenv.Dotenv(env), optenv.Override()}, want: config{BaseURL: "http://file", APITimeout: "1s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := optenv.Provide[config]("OPTENV", tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := options.Apply(new(config), opts...); *got != tt.want {
+				t.Errorf("Apply(Provide()) = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+
+	write(env, "broken")
+	if _, err := optenv.Provide[config]("OPTENV", optenv.Dotenv(env)); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Provide() error = %v, want the line of the broken file", err)
+	}
+}
@@ -0,0 +1,220 @@
+// Package optenv translates environment variables into options, so
+// services configured through the environment can construct their clients
+// without glue code:
+//
+//	opts, err := optenv.Provide[Client]("CLIENT")
+//	if err != nil {
+//		return err
+//	}
+//	client := options.Apply(new(Client), opts...)
+//
+// The variable of a field is the prefix followed by the upper case field
+// key, see the options package, such as CLIENT_BASE_URL for baseURL. The
+// env item of the `option` tag replaces the derived name, e.g.
+// `option:"env=API_URL"` reads API_URL regardless of the prefix. Fields of
+// nested structs declared in the same package are read with the key of the
+// field holding them, such as CLIENT_RETRY_MAX_ATTEMPTS, and map fields
+// collect every variable starting with their name, such as
+// CLIENT_HEADER_Authorization.
+//
+// For local development, the variables can be kept in .env files, which
+// the environment takes precedence over:
+//
+//	opts, err := optenv.Provide[Client]("CLIENT", optenv.Dotenv(".env", ".env.local"))
+package optenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// Config controls where the variables are read from.
+type Config struct {
+	// Dotenv are the .env files read in addition to the environment, later
+	// files taking precedence over earlier ones.
+	Dotenv []string
+	// Override gives the variables of the .env files precedence over those
+	// of the environment.
+	Override bool
+}
+
+// Dotenv reads the variables of the .env files at paths as well, unless
+// the environment sets them. Files that do not exist are skipped, so the
+// same code runs where no .env file is deployed. See ParseDotenv for the
+// syntax.
+func Dotenv(paths ...string) options.Option[Config] {
+	return options.Named("Dotenv", paths, func(c *Config) {
+		c.Dotenv = append(c.Dotenv, paths...)
+	})
+}
+
+// Override makes the variables of the .env files take precedence over
+// those of the environment.
+func Override() options.Option[Config] {
+	return options.Named("Override", true, func(c *Config) {
+		c.Override = true
+	})
+}
+
+// Provide returns an option for every variable of the environment that
+// sets a field of the struct type T. Values are parsed like the strings of
+// options.FromMap; every unparsable value is reported.
+func Provide[T any](prefix string, opts ...options.Option[Config]) ([]options.Option[T], error) {
+	cfg := options.Apply(new(Config), opts...)
+	var dotenv []string
+	for _, path := range cfg.Dotenv {
+		vars, err := readDotenv(path)
+		if err != nil {
+			return nil, err
+		}
+		dotenv = append(dotenv, vars...)
+	}
+	if cfg.Override {
+		return FromEnviron[T](prefix, append(os.Environ(), dotenv...))
+	}
+	return FromEnviron[T](prefix, append(dotenv, os.Environ()...))
+}
+
+// FromEnviron is like Provide but reads the variables from environ, a list
+// of key=value pairs in the form of os.Environ. Later pairs take precedence
+// over earlier ones with the same key.
+func FromEnviron[T any](prefix string, environ []string) ([]options.Option[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("optenv: %s is not a struct", t)
+	}
+	env := map[string]string{}
+	for _, kv := range environ {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	if prefix != "" {
+		prefix = strings.TrimSuffix(prefix, "_") + "_"
+	}
+
+	var (
+		opts []options.Option[T]
+		errs []error
+	)
+	for _, v := range Variables(t, prefix) {
+		value, err := v.lookup(env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", v.Name, err))
+			continue
+		}
+		if value.IsValid() {
+			opts = append(opts, setField[T](v, value))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return opts, nil
+}
+
+// Variable is an environment variable read for a field.
+type Variable struct {
+	// Name is the name of the variable, the prefix of the variables read
+	// for a map field.
+	Name string
+	// Path is the dotted path of the field, such as retry.maxAttempts.
+	Path string
+	// Type is the type of the field.
+	Type reflect.Type
+	// Map reports whether the field collects the variables starting with
+	// Name followed by an underscore.
+	Map bool
+
+	index [][]int
+}
+
+// Variables returns the environment variables read for the fields of the
+// struct type t with prefix, in declaration order.
+func Variables(t reflect.Type, prefix string) []Variable {
+	return variables(nil, t, prefix, "", nil, map[reflect.Type]bool{t: true})
+}
+
+// variables appends the variables of the fields of t to out. chain holds the
+// structs being descended into, which recursive types are not read again.
+func variables(out []Variable, t reflect.Type, prefix, path string, index [][]int, chain map[reflect.Type]bool) []Variable {
+	for _, f := range fields.Fields(t) {
+		name := prefix + strings.ToUpper(f.Key)
+		if env := f.Tag.Items["env"]; env != "" {
+			name = env
+		}
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+		fieldIndex := append(append([][]int(nil), index...), f.Index)
+
+		nested := f.Type
+		if nested.Kind() == reflect.Pointer {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && nested.PkgPath() == t.PkgPath() && !fields.TextUnmarshaler(f.Type) {
+			if !chain[nested] {
+				chain[nested] = true
+				out = variables(out, nested, name+"_", fieldPath, fieldIndex, chain)
+				delete(chain, nested)
+			}
+			continue
+		}
+		v := Variable{Name: name, Path: fieldPath, Type: f.Type, index: fieldIndex}
+		if f.Type.Kind() == reflect.Map {
+			v.Map = f.Type.Key().Kind() == reflect.String
+			if !v.Map {
+				continue
+			}
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// lookup returns the value of v in env, or the zero Value if v is not set.
+func (v Variable) lookup(env map[string]string) (reflect.Value, error) {
+	if !v.Map {
+		s, ok := env[v.Name]
+		if !ok {
+			return reflect.Value{}, nil
+		}
+		return fields.Convert(s, v.Type)
+	}
+
+	var keys []string
+	for key := range env {
+		if strings.HasPrefix(key, v.Name+"_") && len(key) > len(v.Name)+1 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return reflect.Value{}, nil
+	}
+	sort.Strings(keys)
+	m := reflect.MakeMapWithSize(v.Type, len(keys))
+	for _, key := range keys {
+		elem, err := fields.Convert(env[key], v.Type.Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", key, err)
+		}
+		m.SetMapIndex(reflect.ValueOf(strings.TrimPrefix(key, v.Name+"_")).Convert(v.Type.Key()), elem)
+	}
+	return m, nil
+}
+
+// setField returns an option named after the variable that assigns value
+// to its field.
+func setField[T any](v Variable, value reflect.Value) options.Option[T] {
+	return options.Named(v.Name, value.Interface(), func(t *T) {
+		fields.Set(reflect.ValueOf(t).Elem(), v.index, value)
+	})
+}
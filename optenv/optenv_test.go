@@ -0,0 +1,82 @@
+package optenv_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/optenv"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type retry struct {
+	MaxAttempts int
+}
+
+type client struct {
+	BaseURL string
+	Timeout time.Duration `option:"env=API_TIMEOUT"`
+	Header  map[string]string
+	Retry   *retry
+}
+
+func TestFromEnviron(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		want    client
+		wantErr string
+	}{
+		{name: "empty", environ: []string{"OTHER=1"}},
+		{
+			name:    "fields",
+			environ: []string{"CLIENT_BASE_URL=http://a", "API_TIMEOUT=5s", "CLIENT_RETRY_MAX_ATTEMPTS=3"},
+			want:    client{BaseURL: "http://a", Timeout: 5 * time.Second, Retry: &retry{MaxAttempts: 3}},
+		},
+		{
+			name:    "map",
+			environ: []string{"CLIENT_HEADER_Authorization=token", "CLIENT_HEADER_=ignored"},
+			want:    client{Header: map[string]string{"Authorization": "token"}},
+		},
+		{
+			name:    "every error",
+			environ: []string{"API_TIMEOUT=x", "CLIENT_RETRY_MAX_ATTEMPTS=y"},
+			wantErr: "API_TIMEOUT: time: invalid duration \"x\"\nCLIENT_RETRY_MAX_ATTEMPTS: strconv.ParseInt: parsing \"y\": invalid syntax",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := optenv.FromEnviron[client]("CLIENT", tt.environ)
+			if got := errorString(err); got != tt.wantErr {
+				t.Fatalf("FromEnviron() error = %q, want %q", got, tt.wantErr)
+			}
+			if got := options.Apply(new(client), opts...); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Apply(FromEnviron()) = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariables(t *testing.T) {
+	var names []string
+	for _, v := range optenv.Variables(reflect.TypeFor[client](), "CLIENT_") {
+		names = append(names, v.Name)
+	}
+	want := []string{"CLIENT_BASE_URL", "API_TIMEOUT", "CLIENT_HEADER", "CLIENT_RETRY_MAX_ATTEMPTS"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Variables() = %q, want %q", names, want)
+	}
+}
+
+func TestFromEnvironNotStruct(t *testing.T) {
+	if _, err := optenv.FromEnviron[int]("", nil); err == nil {
+		t.Error("FromEnviron[int]() succeeded")
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,167 @@
+// Package optfile translates configuration files into options. Unlike
+// unmarshalling a file into the struct, only the keys present in the file
+// produce options, so the defaults of absent fields are kept:
+//
+//	opts, err := optfile.JSON[Client]("client.json")
+//	if err != nil {
+//		return err
+//	}
+//	client := clientDefaults.Apply(new(Client), opts...)
+//
+// Keys are matched against the field keys of the struct as described for
+// options.FromMap, and objects set the fields of nested structs they have
+// keys for. Keys without a matching field are ignored, so one file can
+// hold the configuration of several structs, unless Strict is given.
+package optfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// Config controls how a file is translated into options.
+type Config struct {
+	// Strict reports the keys without a matching field instead of ignoring
+	// them.
+	Strict bool
+}
+
+// Strict makes the provider fail on keys without a matching field, which
+// catches misspelled keys in files configuring a single struct.
+func Strict() options.Option[Config] {
+	return options.Named("Strict", true, func(c *Config) {
+		c.Strict = true
+	})
+}
+
+// JSON reads the JSON object in the file at path and returns an option for
+// every key that sets a field of the struct type T. Integers keep their
+// precision beyond 2^53, so IDs in int64 and uint64 fields are set exactly.
+func JSON[T any](path string, opts ...options.Option[Config]) ([]options.Option[T], error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("optfile: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("optfile: %s: %w", path, err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("optfile: %s: unexpected data after the object", path)
+	}
+	if err := numbers(doc); err != nil {
+		return nil, fmt.Errorf("optfile: %s: %w", path, err)
+	}
+	return provide[T](path, doc, opts)
+}
+
+// numbers replaces the json.Number values in doc, in place, by int64 or uint64 when
+// they are integers, like the YAML and TOML decoders produce, and float64
+// otherwise. Decoding into float64 right away would round integers beyond
+// 2^53 before their fields are set.
+func numbers(doc map[string]any) error {
+	var convert func(v any) (any, error)
+	convert = func(v any) (any, error) {
+		switch v := v.(type) {
+		case json.Number:
+			if n, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+				return n, nil
+			}
+			if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+				return n, nil
+			}
+			f, err := v.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %s: %w", v, err)
+			}
+			return f, nil
+		case map[string]any:
+			for key, elem := range v {
+				elem, err := convert(elem)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: %w", key, err)
+				}
+				v[key] = elem
+			}
+		case []any:
+			for i, elem := range v {
+				elem, err := convert(elem)
+				if err != nil {
+					return nil, fmt.Errorf("index %d: %w", i, err)
+				}
+				v[i] = elem
+			}
+		}
+		return v, nil
+	}
+	_, err := convert(doc)
+	return err
+}
+
+// YAML reads the YAML mapping in the file at path and returns an option for
+// every key that sets a field of the struct type T. Anchors, aliases and
+// merge keys are resolved, so shared settings can be declared once:
+//
+//	x-retry: &retry
+//	  max_attempts: 3
+//	  backoff: 100ms
+//	base_url: https://api.example.com
+//	retry:
+//	  <<: *retry
+//	  max_attempts: 5
+//
+// An empty file yields no options.
+func YAML[T any](path string, opts ...options.Option[Config]) ([]options.Option[T], error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("optfile: %w", err)
+	}
+	var doc map[string]any
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("optfile: %s: %w", path, err)
+	}
+	return provide[T](path, doc, opts)
+}
+
+// TOML reads the TOML document in the file at path and returns an option
+// for every key that sets a field of the struct type T. Tables set the
+// fields of nested structs, so a [retry] table configures the retry field.
+func TOML[T any](path string, opts ...options.Option[Config]) ([]options.Option[T], error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("optfile: %w", err)
+	}
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("optfile: %s: %w", path, err)
+	}
+	return provide[T](path, doc, opts)
+}
+
+// provide translates the decoded document doc of the file at path into
+// options.
+func provide[T any](path string, doc map[string]any, settings []options.Option[Config]) ([]options.Option[T], error) {
+	cfg := options.Apply(new(Config), settings...)
+	if !cfg.Strict {
+		doc = fields.Known(reflect.TypeFor[T](), doc)
+	}
+	opts, err := options.FromMap[T](doc)
+	if err != nil {
+		return nil, fmt.Errorf("optfile: %s: %w", path, err)
+	}
+	return opts, nil
+}
@@ -0,0 +1,147 @@
+package optfile_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/optfile"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type retry struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+type client struct {
+	BaseURL string
+	Timeout time.Duration
+	Retry   retry
+}
+
+var defaults = client{Timeout: time.Second, Retry: retry{MaxAttempts: 1, Backoff: time.Millisecond}}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    client
+		wantErr bool
+	}{
+		{name: "empty", data: `{}`, want: defaults},
+		{
+			name: "present keys",
+			data: `{"base_url": "http://a", "retry": {"max_attempts": 3}, "other": true}`,
+			want: client{BaseURL: "http://a", Timeout: time.Second, Retry: retry{MaxAttempts: 3, Backoff: time.Millisecond}},
+		},
+		{name: "invalid value", data: `{"timeout": "x"}`, wantErr: true},
+		{name: "syntax", data: `{`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := optfile.JSON[client](writeFile(t, "client.json", tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := defaults
+			if options.Apply(&got, opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply(JSON()) = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONMissing(t *testing.T) {
+	if _, err := optfile.JSON[client](filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("JSON() error = %v, want a not exist error", err)
+	}
+}
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestYAML(t *testing.T) {
+	data := `x-retry: &retry
+  max_attempts: 3
+  backoff: 100ms
+base_url: http://a
+retry:
+  <<: *retry
+  max_attempts: 5
+`
+	opts, err := optfile.YAML[client](writeFile(t, "client.yaml", data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := client{BaseURL: "http://a", Timeout: time.Second, Retry: retry{MaxAttempts: 5, Backoff: 100 * time.Millisecond}}
+	got := defaults
+	if options.Apply(&got, opts...); !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply(YAML()) = %+v, want %+v", got, want)
+	}
+
+	if opts, err := optfile.YAML[client](writeFile(t, "empty.yaml", "")); err != nil || len(opts) != 0 {
+		t.Errorf("YAML(empty) = %d options, %v, want none", len(opts), err)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	path := writeFile(t, "client.json", `{"base_url": "http://a", "base_ulr": "http://b"}`)
+	if _, err := optfile.JSON[client](path); err != nil {
+		t.Errorf("JSON() error = %v, want unknown keys ignored", err)
+	}
+	if _, err := optfile.JSON[client](path, optfile.Strict()); err == nil {
+		t.Error("JSON(Strict()) succeeded with an unknown key")
+	}
+}
+
+func TestTOML(t *testing.T) {
+	data := `base_url = "http://a"
+
+[retry]
+max_attempts = 3
+`
+	opts, err := optfile.TOML[client](writeFile(t, "client.toml", data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := client{BaseURL: "http://a", Timeout: time.Second, Retry: retry{MaxAttempts: 3, Backoff: time.Millisecond}}
+	got := defaults
+	if options.Apply(&got, opts...); !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply(TOML()) = %+v, want %+v", got, want)
+	}
+}
+
+func TestJSONNumbers(t *testing.T) {
+	type ids struct {
+		Signed   int64
+		Unsigned uint64
+		Ratio    float64
+	}
+	path := writeFile(t, "ids.json", `{"signed": 9007199254740993, "unsigned": 18446744073709551615, "ratio": 0.5}`)
+	opts, err := optfile.JSON[ids](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ids{Signed: 9007199254740993, Unsigned: 18446744073709551615, Ratio: 0.5}
+	if got := options.Apply(new(ids), opts...); *got != want {
+		t.Errorf("Apply(JSON()) = %+v, want %+v", *got, want)
+	}
+
+	if _, err := optfile.JSON[ids](writeFile(t, "trailing.json", `{} {}`)); err == nil {
+		t.Error("JSON() succeeded with data after the object")
+	}
+}
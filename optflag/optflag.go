@@ -0,0 +1,148 @@
+// Package optflag translates command line flags into options, so a CLI can
+// configure a struct through the flag package without declaring a variable
+// per flag:
+//
+//	parsed := optflag.Bind[Client](flag.CommandLine)
+//	flag.Parse()
+//	client, err := New(baseURL, parsed()...)
+//
+// Bind defines a flag per field of a type it can parse: strings, booleans,
+// numbers, durations, types implementing encoding.TextUnmarshaler, and slices
+// of those given as comma separated lists. The flag name is the field key,
+// see the options package, with dashes instead of underscores, such as
+// -base-url for baseURL, unless the flag item of the `option` tag sets one,
+// e.g. `option:"flag=url"`. The usage string comes from the `usage` struct
+// tag, or names the field. Fields of nested structs declared in the same
+// package get flags named after the field holding them, such as
+// -retry-max-attempts.
+package optflag
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// Bind defines the flags of the fields of the struct type T on fs and
+// returns a function that, once fs is parsed, returns an option for every
+// flag given on the command line. Fields whose flag is not given keep the
+// value they get otherwise, such as their default. Invalid values are
+// reported by fs.Parse.
+func Bind[T any](fs *flag.FlagSet) func() []options.Option[T] {
+	var values []*value
+	t := reflect.TypeFor[T]()
+	bind(fs, t, "", nil, map[reflect.Type]bool{t: true}, &values)
+	return func() []options.Option[T] {
+		var opts []options.Option[T]
+		for _, v := range values {
+			if v.set {
+				opts = append(opts, setField[T](v))
+			}
+		}
+		return opts
+	}
+}
+
+// bind defines the flags of the fields of the struct type t, prefixed with
+// prefix, and appends their values. chain holds the structs being descended
+// into, which recursive types are not bound again.
+func bind(fs *flag.FlagSet, t reflect.Type, prefix string, index [][]int, chain map[reflect.Type]bool, values *[]*value) {
+	for _, f := range fields.Fields(t) {
+		name := prefix + strings.ReplaceAll(f.Key, "_", "-")
+		if item := f.Tag.Items["flag"]; item != "" {
+			name = item
+		}
+		fieldIndex := append(append([][]int(nil), index...), f.Index)
+
+		nested := f.Type
+		if nested.Kind() == reflect.Pointer {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && nested.PkgPath() == t.PkgPath() && !fields.TextUnmarshaler(f.Type) {
+			if !chain[nested] {
+				chain[nested] = true
+				bind(fs, nested, name+"-", fieldIndex, chain, values)
+				delete(chain, nested)
+			}
+			continue
+		}
+		if !parsable(f.Type) {
+			continue
+		}
+		usage := f.StructField.Tag.Get("usage")
+		if usage == "" {
+			usage = "sets the " + f.Name + " field of " + t.Name()
+		}
+		v := &value{name: name, typ: f.Type, index: fieldIndex}
+		fs.Var(v, name, usage)
+		*values = append(*values, v)
+	}
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// parsable reports whether values of t can be given as a flag.
+func parsable(t reflect.Type) bool {
+	if t == durationType || fields.TextUnmarshaler(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Slice && parsable(t.Elem())
+	}
+	return false
+}
+
+// value is the flag.Value of a field. It records whether the flag was set,
+// so only given flags produce options.
+type value struct {
+	name   string
+	typ    reflect.Type
+	index  [][]int
+	parsed reflect.Value
+	set    bool
+}
+
+func (v *value) String() string {
+	if v == nil || !v.set {
+		return ""
+	}
+	if m, ok := v.parsed.Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(v.parsed.Interface())
+}
+
+func (v *value) Set(s string) error {
+	parsed, err := fields.Convert(s, v.typ)
+	if err != nil {
+		return err
+	}
+	v.parsed, v.set = parsed, true
+	return nil
+}
+
+// IsBoolFlag lets boolean fields be set by -name alone.
+func (v *value) IsBoolFlag() bool {
+	return v.typ.Kind() == reflect.Bool
+}
+
+// setField returns an option named after the flag that assigns its value.
+func setField[T any](v *value) options.Option[T] {
+	return options.Named(v.name, v.parsed.Interface(), func(t *T) {
+		fields.Set(reflect.ValueOf(t).Elem(), v.index, v.parsed)
+	})
+}
@@ -0,0 +1,71 @@
+package optflag_test
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/optflag"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type retry struct {
+	MaxAttempts int
+}
+
+type client struct {
+	BaseURL string        `usage:"the URL requests are sent to"`
+	Timeout time.Duration `option:"flag=t"`
+	Verbose bool
+	Tags    []string
+	Retry   retry
+	Opaque  map[string]string
+}
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    client
+		wantErr bool
+	}{
+		{name: "none", want: client{Timeout: time.Second}},
+		{
+			name: "given",
+			args: []string{"-base-url", "http://a", "-t", "5s", "-verbose", "-tags", "x,y", "-retry-max-attempts", "3"},
+			want: client{BaseURL: "http://a", Timeout: 5 * time.Second, Verbose: true, Tags: []string{"x", "y"}, Retry: retry{MaxAttempts: 3}},
+		},
+		{name: "invalid", args: []string{"-retry-max-attempts", "x"}, wantErr: true},
+		{name: "unbound", args: []string{"-opaque", "x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("client", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			parsed := optflag.Bind[client](fs)
+			if err := fs.Parse(tt.args); (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := client{Timeout: time.Second}
+			if options.Apply(&got, parsed()...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply(Bind()()) = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindUsage(t *testing.T) {
+	fs := flag.NewFlagSet("client", flag.ContinueOnError)
+	optflag.Bind[client](fs)
+	if got, want := fs.Lookup("base-url").Usage, "the URL requests are sent to"; got != want {
+		t.Errorf("base-url usage = %q, want %q", got, want)
+	}
+	if got, want := fs.Lookup("verbose").Usage, "sets the Verbose field of client"; got != want {
+		t.Errorf("verbose usage = %q, want %q", got, want)
+	}
+}
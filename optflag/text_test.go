@@ -0,0 +1,28 @@
+package optflag_test
+
+import (
+	"flag"
+	"net/netip"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/optflag"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestBindText(t *testing.T) {
+	type listener struct {
+		Addr netip.Addr
+	}
+	fs := flag.NewFlagSet("listener", flag.ContinueOnError)
+	parsed := optflag.Bind[listener](fs)
+	if err := fs.Parse([]string{"-addr", "::1"}); err != nil {
+		t.Fatal(err)
+	}
+	want := netip.MustParseAddr("::1")
+	if got := options.Apply(new(listener), parsed()...); got.Addr != want {
+		t.Errorf("Apply(Bind()()).Addr = %v, want %v", got.Addr, want)
+	}
+	if got := fs.Lookup("addr").Value.String(); got != "::1" {
+		t.Errorf("-addr value = %q, want the text form ::1", got)
+	}
+}
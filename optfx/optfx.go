@@ -0,0 +1,54 @@
+// Package optfx exposes types configured through options to the
+// go.uber.org/fx dependency injection framework. Options are values in the
+// object graph, so modules contribute the settings they own and the
+// constructor receives all of them:
+//
+//	fx.New(
+//		optfx.Provide(newClient),
+//		optfx.Supply(WithBaseURL("https://api.example.com")),
+//		optfx.Contribute(func(cfg *Config) options.OptionE[Client] {
+//			return WithTimeout(cfg.Timeout)
+//		}),
+//		fx.Invoke(func(c *Client) { ... }),
+//	)
+//
+// The options of a type are collected in a value group, which fx fills in
+// no particular order; options contributed by different modules should
+// therefore configure different fields.
+//
+// The package is a module of its own, so fx is only required by the users
+// of this package and not by those of the options package.
+package optfx
+
+import "go.uber.org/fx"
+
+// group is the value group holding the options; fx keys groups by name and
+// type, so the options of every type have a group of their own.
+const group = `group:"options"`
+
+// Provide returns an fx option providing the *T built by ctor from all
+// options of type O in the graph, such as the constructor generated by
+// optiongen taking ...options.OptionE[T].
+func Provide[T, O any](ctor func(opts ...O) (*T, error)) fx.Option {
+	return fx.Provide(fx.Annotate(func(opts []O) (*T, error) {
+		return ctor(opts...)
+	}, fx.ParamTags(group)))
+}
+
+// Supply returns an fx option adding opts to the options passed to the
+// constructor of Provide.
+func Supply[O any](opts ...O) fx.Option {
+	provides := make([]fx.Option, len(opts))
+	for i, opt := range opts {
+		provides[i] = fx.Provide(fx.Annotate(func() O { return opt }, fx.ResultTags(group)))
+	}
+	return fx.Options(provides...)
+}
+
+// Contribute returns an fx option adding the option returned by fn to the
+// options passed to the constructor of Provide. fn is an fx constructor
+// whose first result is the option, so it can depend on other values of
+// the graph such as a configuration struct.
+func Contribute(fn any) fx.Option {
+	return fx.Provide(fx.Annotate(fn, fx.ResultTags(group)))
+}
@@ -0,0 +1,80 @@
+package optfx_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"go.uber.org/fx"
+
+	"github.com/StevenCyb/golang-functional-options/optfx"
+)
+
+type client struct {
+	baseURL string
+	tags    []string
+}
+
+type option func(*client) error
+
+func newClient(opts ...option) (*client, error) {
+	c := new(client)
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func withBaseURL(u string) option {
+	return func(c *client) error {
+		if u == "" {
+			return errors.New("empty base URL")
+		}
+		c.baseURL = u
+		return nil
+	}
+}
+
+func withTag(tag string) option {
+	return func(c *client) error {
+		c.tags = append(c.tags, tag)
+		return nil
+	}
+}
+
+type config struct {
+	tag string
+}
+
+func TestProvide(t *testing.T) {
+	var got *client
+	app := fx.New(
+		fx.NopLogger,
+		optfx.Provide(newClient),
+		optfx.Supply(withBaseURL("http://a"), withTag("supplied")),
+		fx.Supply(&config{tag: "contributed"}),
+		optfx.Contribute(func(cfg *config) option { return withTag(cfg.tag) }),
+		fx.Populate(&got),
+	)
+	if err := app.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got.baseURL != "http://a" {
+		t.Errorf("baseURL = %q, want http://a", got.baseURL)
+	}
+	// fx fills the group in no particular order.
+	slices.Sort(got.tags)
+	if want := []string{"contributed", "supplied"}; !slices.Equal(got.tags, want) {
+		t.Errorf("tags = %q, want %q", got.tags, want)
+	}
+}
+
+func TestProvideError(t *testing.T) {
+	var got *client
+	app := fx.New(fx.NopLogger, optfx.Provide(newClient), optfx.Supply(withBaseURL("")), fx.Populate(&got))
+	if app.Err() == nil {
+		t.Error("fx.New() succeeded with a failing option")
+	}
+}
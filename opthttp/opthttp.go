@@ -0,0 +1,204 @@
+// Package opthttp is the HTTP client of the examples grown into a package:
+// client-level options set the defaults of every request, and the options
+// passed to Do override them for a single request:
+//
+//	client, err := opthttp.New("https://api.example.com",
+//		opthttp.WithDefaults(
+//			opthttp.WithHeader("Authorization", "Bearer token"),
+//			opthttp.WithTimeout(10*time.Second),
+//		),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/users", nil)
+//	resp, err := client.Do(req, opthttp.WithTimeout(time.Second), opthttp.WithRetry(3, 100*time.Millisecond))
+package opthttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// Client sends requests relative to a base URL.
+type Client struct {
+	baseURL    *url.URL
+	baseClient *http.Client
+	defaults   Request
+}
+
+// Request holds the settings a request is sent with. The client holds the
+// defaults, which the options passed to Do are applied to a copy of.
+type Request struct {
+	header      http.Header
+	timeout     time.Duration
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// RequestOption configures a request, as default of the client through
+// WithDefaults or for a single request through Do.
+type RequestOption = options.OptionE[Request]
+
+// New creates a client resolving the URLs of requests against baseURL.
+func New(baseURL string, opts ...options.OptionE[Client]) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("opthttp: %w", err)
+	}
+	if !u.IsAbs() {
+		return nil, fmt.Errorf("opthttp: base URL %q is not absolute", baseURL)
+	}
+	c := &Client{
+		baseURL:    u,
+		baseClient: http.DefaultClient,
+		defaults:   Request{header: http.Header{}, maxAttempts: 1},
+	}
+	if err := options.ApplyE(c, opts...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// WithHTTPClient sets the client sending the requests, http.DefaultClient
+// by default.
+func WithHTTPClient(client *http.Client) options.OptionE[Client] {
+	return options.NamedE("WithHTTPClient", client, func(c *Client) error {
+		if client == nil {
+			return errors.New("client must not be nil")
+		}
+		c.baseClient = client
+		return nil
+	})
+}
+
+// WithDefaults applies opts to the settings every request starts from.
+func WithDefaults(opts ...RequestOption) options.OptionE[Client] {
+	return options.SubE(func(c *Client) *Request { return &c.defaults }, opts...)
+}
+
+// WithHeader sets the header key to value. Headers set on the request
+// itself take precedence.
+func WithHeader(key, value string) RequestOption {
+	return options.NamedE("WithHeader", key, func(r *Request) error {
+		r.header.Set(key, value)
+		return nil
+	})
+}
+
+// WithTimeout bounds each attempt of a request to d, including reading the
+// response body. Zero disables the timeout.
+func WithTimeout(d time.Duration) RequestOption {
+	return options.NamedE("WithTimeout", d, func(r *Request) error {
+		if d < 0 {
+			return fmt.Errorf("timeout %s is negative", d)
+		}
+		r.timeout = d
+		return nil
+	})
+}
+
+// WithRetry makes a request up to maxAttempts times, waiting backoff
+// between attempts, while it fails with a network error or a status of 429
+// or 5xx. Requests with a body are retried only if it can be read again,
+// see http.Request.GetBody.
+func WithRetry(maxAttempts int, backoff time.Duration) RequestOption {
+	return options.NamedE("WithRetry", maxAttempts, func(r *Request) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf("max attempts %d is less than 1", maxAttempts)
+		}
+		if backoff < 0 {
+			return fmt.Errorf("backoff %s is negative", backoff)
+		}
+		r.maxAttempts, r.backoff = maxAttempts, backoff
+		return nil
+	})
+}
+
+// Do sends req with the defaults of the client overridden by opts. A
+// relative request URL, such as one of http.NewRequest(method, "/users",
+// nil), is resolved against the base URL. The caller closes the body of
+// the response as usual.
+func (c *Client) Do(req *http.Request, opts ...RequestOption) (*http.Response, error) {
+	settings := c.defaults
+	settings.header = c.defaults.header.Clone()
+	if err := options.ApplyE(&settings, opts...); err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	if !req.URL.IsAbs() {
+		req.URL = c.baseURL.ResolveReference(req.URL)
+		req.Host = ""
+	}
+	for key, values := range settings.header {
+		if _, ok := req.Header[key]; !ok {
+			req.Header[key] = values
+		}
+	}
+
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(req, settings.timeout)
+		if attempt >= settings.maxAttempts || !replayable || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(settings.backoff):
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// send makes a single attempt of req bounded by timeout.
+func (c *Client) send(req *http.Request, timeout time.Duration) (*http.Response, error) {
+	if timeout == 0 {
+		return c.baseClient.Do(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := c.baseClient.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// retryable reports whether an attempt ending with resp or err is repeated.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// Giving up is up to the context of the request.
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// cancelBody releases the context of an attempt once the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,99 @@
+package opthttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/opthttp"
+)
+
+func TestDo(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+		w.Header().Set("X-Agent", r.Header.Get("User-Agent"))
+		w.Header().Set("X-Token", r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	client, err := opthttp.New(srv.URL+"/api/", opthttp.WithDefaults(
+		opthttp.WithHeader("Authorization", "Bearer default"),
+		opthttp.WithHeader("User-Agent", "opthttp"),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/flaky", nil)
+	req.Header.Set("Authorization", "Bearer request")
+	resp, err := client.Do(req, opthttp.WithRetry(3, 0), opthttp.WithHeader("User-Agent", "override"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || attempts.Load() != 3 {
+		t.Errorf("Do() = %s after %d attempts, want 200 OK after 3", resp.Status, attempts.Load())
+	}
+	if got := resp.Header.Get("X-Token"); got != "Bearer request" {
+		t.Errorf("Authorization = %q, want the header of the request", got)
+	}
+	if got := resp.Header.Get("X-Agent"); got != "override" {
+		t.Errorf("User-Agent = %q, want the header of the option of Do", got)
+	}
+
+	// The options of a request do not change the defaults.
+	attempts.Store(0)
+	req, _ = http.NewRequest(http.MethodGet, "/flaky", nil)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("X-Agent") != "" {
+		t.Errorf("Do() = %s, want a single failed attempt", resp.Status)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/slow", nil)
+	if _, err := client.Do(req, opthttp.WithTimeout(10*time.Millisecond)); err == nil {
+		t.Error("Do(WithTimeout()) succeeded on a slow server")
+	}
+	if _, err := client.Do(req, opthttp.WithTimeout(-time.Second)); err == nil || !strings.Contains(err.Error(), "negative") {
+		t.Errorf("Do(WithTimeout(-1s)) error = %v, want negative", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		baseURL string
+		opts    []opthttp.RequestOption
+	}{
+		{name: "relative", baseURL: "/api"},
+		{name: "malformed", baseURL: "http://a b"},
+		{name: "invalid default", baseURL: "http://a", opts: []opthttp.RequestOption{opthttp.WithRetry(0, 0)}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := opthttp.New(tt.baseURL, opthttp.WithDefaults(tt.opts...)); err == nil {
+				t.Errorf("New(%q) succeeded", tt.baseURL)
+			}
+		})
+	}
+	if _, err := opthttp.New("http://a", opthttp.WithHTTPClient(nil)); err == nil {
+		t.Error("New(WithHTTPClient(nil)) succeeded")
+	}
+}
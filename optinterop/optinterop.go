@@ -0,0 +1,83 @@
+// Package optinterop converts between the options of this module and the
+// option types of third-party APIs, so services mixing libraries manage
+// every setting in one option pipeline.
+//
+// Foreign options declared as functions of a pointer, such as
+// `type ProducerOption func(*Producer)`, convert both ways with Func and
+// FromFunc. Opaque option values such as grpc.DialOption cannot be
+// inspected; Collect carries them through the pipeline in a field of the
+// configured struct, and Adapt derives them from the configured struct:
+//
+//	type Dial struct {
+//		timeout time.Duration
+//		extra   []grpc.DialOption
+//	}
+//
+//	func dialOptions(d *Dial) []grpc.DialOption {
+//		return append([]grpc.DialOption{grpc.WithIdleTimeout(d.timeout)}, d.extra...)
+//	}
+//
+//	conn, err := grpc.NewClient(target, optinterop.Adapt(dialOptions,
+//		WithTimeout(time.Minute),
+//		optinterop.Collect(func(d *Dial) *[]grpc.DialOption { return &d.extra }, grpc.WithUserAgent("svc")),
+//	)...)
+package optinterop
+
+import "github.com/StevenCyb/golang-functional-options/options"
+
+// Func converts opt into the foreign option type F, a function of *T.
+func Func[F ~func(*T), T any](opt options.Option[T]) F {
+	if opt == nil {
+		return nil
+	}
+	return F(opt)
+}
+
+// Funcs converts opts into the foreign option type F, keeping their order.
+func Funcs[F ~func(*T), T any](opts ...options.Option[T]) []F {
+	out := make([]F, 0, len(opts))
+	for _, opt := range opts {
+		if opt != nil {
+			out = append(out, F(opt))
+		}
+	}
+	return out
+}
+
+// FromFunc converts the foreign option f, a function of *T, into an option.
+func FromFunc[T any, F ~func(*T)](f F) options.Option[T] {
+	if f == nil {
+		return nil
+	}
+	return options.Option[T](f)
+}
+
+// FromFuncs converts the foreign options fs into options, keeping their
+// order.
+func FromFuncs[T any, F ~func(*T)](fs ...F) []options.Option[T] {
+	out := make([]options.Option[T], 0, len(fs))
+	for _, f := range fs {
+		if f != nil {
+			out = append(out, options.Option[T](f))
+		}
+	}
+	return out
+}
+
+// Collect returns an option appending the foreign options to the slice
+// returned by field, so they are applied in order with the other options
+// and handed on by the code reading the configured struct.
+func Collect[T, F any](field func(*T) *[]F, foreign ...F) options.Option[T] {
+	return func(t *T) {
+		if s := field(t); s != nil {
+			*s = append(*s, foreign...)
+		}
+	}
+}
+
+// Adapt applies opts to a new T and returns the foreign options convert
+// derives from the result, such as the grpc.DialOption values for the
+// configured timeouts and the options collected with Collect.
+func Adapt[T, F any](convert func(*T) []F, opts ...options.Option[T]) []F {
+	return convert(options.Apply(new(T), opts...))
+}
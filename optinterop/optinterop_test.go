@@ -0,0 +1,74 @@
+package optinterop_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/optinterop"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type producer struct {
+	topic string
+	acks  int
+}
+
+// producerOption is the option type of a foreign API.
+type producerOption func(*producer)
+
+func withTopic(topic string) options.Option[producer] {
+	return func(p *producer) { p.topic = topic }
+}
+
+func withAcks(acks int) producerOption {
+	return func(p *producer) { p.acks = acks }
+}
+
+func TestFuncs(t *testing.T) {
+	foreign := optinterop.Funcs[producerOption](withTopic("a"), nil, withTopic("b"))
+	if len(foreign) != 2 {
+		t.Fatalf("Funcs() returned %d options, want 2", len(foreign))
+	}
+	got := new(producer)
+	for _, f := range append(foreign, optinterop.Func[producerOption](withTopic("c"))) {
+		f(got)
+	}
+	if got.topic != "c" {
+		t.Errorf("applied Funcs() topic = %q, want c", got.topic)
+	}
+	if optinterop.Func[producerOption, producer](nil) != nil {
+		t.Error("Func(nil) != nil")
+	}
+
+	opts := append(optinterop.FromFuncs[producer](withAcks(1), nil), optinterop.FromFunc[producer](withAcks(2)))
+	if got := options.Apply(new(producer), opts...); *got != (producer{acks: 2}) {
+		t.Errorf("Apply(FromFuncs()) = %+v, want acks 2", *got)
+	}
+	if optinterop.FromFunc[producer, producerOption](nil) != nil {
+		t.Error("FromFunc(nil) != nil")
+	}
+}
+
+// dialOption is an opaque foreign option.
+type dialOption struct{ name string }
+
+type dial struct {
+	timeout time.Duration
+	extra   []dialOption
+}
+
+func TestAdapt(t *testing.T) {
+	extra := func(d *dial) *[]dialOption { return &d.extra }
+	got := optinterop.Adapt(func(d *dial) []dialOption {
+		return append([]dialOption{{name: "timeout=" + d.timeout.String()}}, d.extra...)
+	},
+		func(d *dial) { d.timeout = time.Minute },
+		optinterop.Collect(extra, dialOption{"user-agent"}),
+		optinterop.Collect(extra, dialOption{"insecure"}),
+	)
+	want := []dialOption{{"timeout=1m0s"}, {"user-agent"}, {"insecure"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Adapt() = %v, want %v", got, want)
+	}
+}
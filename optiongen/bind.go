@@ -0,0 +1,117 @@
+package optiongen
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// binding is an option whose value can be parsed from a string, the common
+// ground of EnvVars and Flags.
+type binding struct {
+	Field Field
+	// Keys are the keys of the fields leading from the struct to Field.
+	Keys []string
+	// Bases are the exported names of the fields leading to Field.
+	Bases []string
+	// Parse is the call parsing the string v, empty when Value uses v
+	// directly.
+	Parse string
+	// Value is the expression passed to the option, using the parsed value
+	// x.
+	Value string
+	// Map reports whether Field is a map[string]string filled entry by
+	// entry.
+	Map bool
+	// Var is the variable collecting the entries of a map field.
+	Var string
+}
+
+// bindings returns the options of s whose value can be parsed from a
+// string, including those of nested structs. Fields tagged `option:"size"`
+// are parsed like their string options, by options.ParseSize. Fields of other types, such
+// as interfaces, as well as deprecated fields are left out.
+func (s *Struct) bindings() []binding {
+	var result []binding
+	used := map[string]bool{"fs": true, "opts": true, "errs": true, "env": true, "name": true, "key": true, "value": true, "ok": true, "v": true, "x": true, "err": true}
+	var walk func(fields []Field, keys, bases []string)
+	walk = func(fields []Field, keys, bases []string) {
+		for _, f := range fields {
+			if f.Deprecated != "" {
+				continue
+			}
+			path := append(slices.Clone(keys), f.Key)
+			names := append(slices.Clone(bases), f.Base)
+			if f.Embedded {
+				// Promoted like encoding/json treats embedded structs.
+				walk(f.Nested, keys, bases)
+				continue
+			}
+			if f.Struct != "" {
+				walk(f.Nested, path, names)
+				continue
+			}
+			if f.Type == "map[string]string" {
+				v := f.Param
+				for i := 2; used[v]; i++ {
+					v = f.Param + strconv.Itoa(i)
+				}
+				used[v] = true
+				result = append(result, binding{Field: f, Keys: path, Bases: names, Map: true, Var: v})
+			} else if f.Size {
+				// size only accepts identifiers, which Ref qualifies
+				// without failing.
+				typ, _ := s.Ref(f.Type)
+				result = append(result, binding{Field: f, Keys: path, Bases: names, Parse: "options.ParseSize[" + typ + "](v)", Value: "x"})
+			} else if parse, value, ok := parseString(f.Type); ok {
+				if f.EnumType != "" {
+					value = f.EnumType + "(" + value + ")"
+				}
+				result = append(result, binding{Field: f, Keys: path, Bases: names, Parse: parse, Value: value})
+			}
+		}
+	}
+	walk(s.Fields, nil, nil)
+	return result
+}
+
+// parseString returns the call parsing a string v into a value of typ and
+// the conversion of the result x to typ.
+func parseString(typ string) (parse, value string, ok bool) {
+	switch typ {
+	case "string":
+		return "", "v", true
+	case "[]string":
+		return "", `strings.Split(v, ",")`, true
+	case "bool":
+		return "strconv.ParseBool(v)", "x", true
+	case "time.Duration":
+		return "time.ParseDuration(v)", "x", true
+	case "float32":
+		return "strconv.ParseFloat(v, 32)", "float32(x)", true
+	case "float64":
+		return "strconv.ParseFloat(v, 64)", "x", true
+	}
+	bits := map[string]string{"8": "8", "16": "16", "32": "32", "64": "64", "": "0"}
+	if size, ok := strings.CutPrefix(typ, "uint"); ok && bits[size] != "" {
+		return "strconv.ParseUint(v, 0, " + bits[size] + ")", conversion(typ, "uint64"), true
+	}
+	if size, ok := strings.CutPrefix(typ, "int"); ok && bits[size] != "" {
+		return "strconv.ParseInt(v, 0, " + bits[size] + ")", conversion(typ, "int64"), true
+	}
+	switch typ {
+	case "byte":
+		return "strconv.ParseUint(v, 0, 8)", "byte(x)", true
+	case "rune":
+		return "strconv.ParseInt(v, 0, 32)", "rune(x)", true
+	}
+	return "", "", false
+}
+
+// conversion converts x, of type parsed, to typ.
+func conversion(typ, parsed string) string {
+	if typ == parsed {
+		return "x"
+	}
+	return typ + "(x)"
+}
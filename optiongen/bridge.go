@@ -0,0 +1,109 @@
+package optiongen
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// Setter is an existing SetX method of the struct taking the value of a
+// field, such as SetHeader(header map[string]string) *Client. With
+// BridgeSetters the option of the field calls it instead of assigning the
+// field, so the validation and side effects of the method are kept.
+type Setter struct {
+	// Name is the name of the method.
+	Name string
+	// Results is the number of results of the method.
+	Results int
+	// Error reports whether the last result is an error, which the option
+	// returns.
+	Error bool
+}
+
+// Blanks returns the blank identifiers for the results in front of the
+// error, such as "_, " for (*Client, error).
+func (s *Setter) Blanks() string {
+	return strings.Repeat("_, ", s.Results-1)
+}
+
+// Bridge returns the call of the setter of the field, such as
+// c.SetHeader(header), or an empty string when the option assigns the field
+// itself.
+func (b BodyData) Bridge() string {
+	if !b.Struct.BridgeSetters || b.Field.Setter == nil {
+		return ""
+	}
+	return b.Receiver + "." + b.Field.Setter.Name + "(" + b.Field.Value() + ")"
+}
+
+// Setters returns the fields with a Setter.
+func (s *Struct) Setters() []Field {
+	var result []Field
+	for _, f := range s.Fields {
+		if f.Setter != nil {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// findSetters records the setters of the fields of the struct typeName
+// declared in files. A setter is a method with a pointer receiver named
+// Set followed by the Base of the field, taking a single parameter of the
+// field type. Methods of that name declared outside generated files set
+// SetMethod, whatever their signature.
+func findSetters(fset *token.FileSet, files []*ast.File, typeName string, fields []Field) error {
+	methods := map[string]*ast.FuncType{}
+	handwritten := map[string]bool{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+				continue
+			}
+			star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+			if !ok || receiverType(star.X) != typeName {
+				continue
+			}
+			methods[fn.Name.Name] = fn.Type
+			handwritten[fn.Name.Name] = handwritten[fn.Name.Name] || !ast.IsGenerated(file)
+		}
+	}
+
+	for i, f := range fields {
+		fields[i].SetMethod = handwritten["Set"+f.Base]
+		fn := methods["Set"+f.Base]
+		if fn == nil || fn.Params.NumFields() != 1 {
+			continue
+		}
+		typ, err := exprString(fset, fn.Params.List[0].Type)
+		if err != nil {
+			return err
+		}
+		if typ != f.Type {
+			continue
+		}
+		setter := &Setter{Name: "Set" + f.Base, Results: fn.Results.NumFields()}
+		if setter.Results > 0 {
+			last := fn.Results.List[len(fn.Results.List)-1].Type
+			id, ok := last.(*ast.Ident)
+			setter.Error = ok && id.Name == "error"
+		}
+		fields[i].Setter = setter
+	}
+	return nil
+}
+
+// receiverType returns the name of the type of a receiver, without the
+// type parameters of a generic type.
+func receiverType(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.IndexExpr:
+		return receiverType(x.X)
+	case *ast.IndexListExpr:
+		return receiverType(x.X)
+	}
+	return ""
+}
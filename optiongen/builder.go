@@ -0,0 +1,86 @@
+package optiongen
+
+import "strings"
+
+// BuilderMethod is a fluent method of the builder generated by ModeBuilder,
+// adding an option of ModeOptions.
+type BuilderMethod struct {
+	// Name is the name of the method, the option name without its With
+	// prefix, such as BaseURL.
+	Name string
+	// Option is the option added by the method.
+	Option string
+	// Params is the parameter list of the method.
+	Params string
+	// Args passes the parameters on to Option.
+	Args string
+}
+
+// Builder returns the name of the builder type generated by ModeBuilder,
+// such as ClientBuilder.
+func (s *Struct) Builder() string {
+	return upperFirst(s.Name) + "Builder"
+}
+
+// BuilderReceiver returns the variable name the builder methods use for the
+// builder, b unless a parameter is called so.
+func (s *Struct) BuilderReceiver() string {
+	for _, m := range s.BuilderMethods() {
+		for _, param := range strings.Split(m.Params, ", ") {
+			if name, _, _ := strings.Cut(param, " "); name == "b" {
+				return "builder"
+			}
+		}
+	}
+	return "b"
+}
+
+// BuilderMethods returns a method per option generated for s, including
+// the options adding a single element, those of nested structs and those
+// of delegates. Deprecated options are left out.
+func (s *Struct) BuilderMethods() []BuilderMethod {
+	var methods []BuilderMethod
+	add := func(option, params, args string) {
+		methods = append(methods, BuilderMethod{Name: strings.TrimPrefix(option, s.OptionPrefix()), Option: option, Params: params, Args: args})
+	}
+	for _, f := range s.Fields {
+		if f.Deprecated != "" {
+			continue
+		}
+		add(f.Option, f.Param+" "+f.ParamType(), f.Param)
+		if f.StringForm() {
+			add(f.Option+"String", f.Param+" string", f.Param)
+		}
+		switch {
+		case f.Item == "":
+		case f.KeyType != "":
+			add(f.Item, "key "+f.KeyType+", "+f.ItemParam+" "+f.Elem, "key, "+f.ItemParam)
+		default:
+			add(f.Item, f.ItemParam+" "+f.Elem, f.ItemParam)
+		}
+	}
+	for _, o := range s.NestedOptions() {
+		add(o.Option, o.Param+" "+o.ParamType(), o.Param)
+		if o.StringForm() {
+			add(o.Option+"String", o.Param+" string", o.Param)
+		}
+	}
+	for _, d := range s.Delegates {
+		add(d.Option, "opts ...options.OptionE["+d.Struct+"]", "opts...")
+	}
+	return methods
+}
+
+// BuilderImports returns the packages needed by the output of ModeBuilder.
+func (s *Struct) BuilderImports() []Import {
+	var fields []Field
+	for _, f := range s.Fields {
+		if f.Deprecated == "" {
+			fields = append(fields, f)
+		}
+	}
+	for _, o := range s.NestedOptions() {
+		fields = append(fields, o.Field)
+	}
+	return s.typeImports(append(fields, s.Delegates...))
+}
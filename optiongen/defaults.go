@@ -0,0 +1,136 @@
+package optiongen
+
+import (
+	"fmt"
+	"go/token"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// DefaultsFunc returns the name of the generated function returning the
+// options that set the defaults, such as clientDefaults.
+func (s *Struct) DefaultsFunc() string {
+	return lowerFirst(s.Name) + "Defaults"
+}
+
+// Defaults returns the options with a default value, including those of
+// nested structs.
+func (s *Struct) Defaults() []Field {
+	var result []Field
+	for _, f := range s.Fields {
+		if f.Default != "" {
+			result = append(result, f)
+		}
+	}
+	for _, o := range s.NestedOptions() {
+		if o.Default != "" {
+			result = append(result, o.Field)
+		}
+	}
+	return result
+}
+
+// DefaultValue returns the Go expression of the default of f in the
+// generated code. A default function is qualified with the package
+// declaring the struct when the options are generated into Pkg.
+func (s *Struct) DefaultValue(f Field) (string, error) {
+	if f.DefaultFunc == "" || s.Pkg == "" {
+		return f.Default, nil
+	}
+	if !token.IsExported(f.DefaultFunc) {
+		return "", fmt.Errorf("default function %s of field %s must be exported to be called from package %s", f.DefaultFunc, f.Name, s.Pkg)
+	}
+	return s.Package + "." + f.Default, nil
+}
+
+// defaultValue translates the default tag of f into a Go expression of the
+// field type. Strings, booleans, numbers and durations are supported, sizes
+// in the forms of options.ParseSize, such as 64MiB, on fields tagged
+// `option:"size"`, and
+// fields of any type can name a function of the package returning the
+// default, such as func=newDefaultHTTPClient.
+func defaultValue(f *Field, tag string) (string, error) {
+	if name, ok := strings.CutPrefix(tag, "func="); ok {
+		if !token.IsIdentifier(name) {
+			return "", fmt.Errorf("invalid default function %q", name)
+		}
+		f.DefaultFunc = name
+		return name + "()", nil
+	}
+	switch {
+	case f.Size:
+		return sizeLiteral(f, tag)
+	case f.Type == "string":
+		return strconv.Quote(tag), nil
+	case f.Type == "bool":
+		v, err := strconv.ParseBool(tag)
+		if err != nil {
+			return "", fmt.Errorf("invalid default %q: %w", tag, err)
+		}
+		return strconv.FormatBool(v), nil
+	case f.Type == "time.Duration":
+		d, err := time.ParseDuration(tag)
+		if err != nil {
+			return "", fmt.Errorf("invalid default %q: %w", tag, err)
+		}
+		return durationLiteral(d), nil
+	case slices.Contains(integers, f.Type):
+		if _, err := strconv.ParseInt(tag, 0, 64); err != nil {
+			return "", fmt.Errorf("invalid default %q: %w", tag, err)
+		}
+		return tag, nil
+	case slices.Contains(floats, f.Type):
+		if _, err := strconv.ParseFloat(tag, 64); err != nil {
+			return "", fmt.Errorf("invalid default %q: %w", tag, err)
+		}
+		return tag, nil
+	}
+	return "", fmt.Errorf("default values are not supported for type %s", f.Type)
+}
+
+// sizeLimits are the largest values of the integer types.
+var sizeLimits = map[string]uint64{
+	"int": math.MaxInt, "int8": math.MaxInt8, "int16": math.MaxInt16, "int32": math.MaxInt32, "int64": math.MaxInt64,
+	"uint": math.MaxUint, "uint8": math.MaxUint8, "uint16": math.MaxUint16, "uint32": math.MaxUint32, "uint64": math.MaxUint64,
+	"uintptr": uint64(^uintptr(0)), "byte": math.MaxUint8, "rune": math.MaxInt32,
+}
+
+// sizeLiteral parses the default tag of the size field f with
+// options.ParseSize and writes it as a number of bytes. The range of types
+// declared in the package is left to the compiler.
+func sizeLiteral(f *Field, tag string) (string, error) {
+	n, err := options.ParseSize[uint64](tag)
+	if err != nil {
+		return "", fmt.Errorf("invalid default: %w", err)
+	}
+	if limit, ok := sizeLimits[f.Type]; ok && n > limit {
+		return "", fmt.Errorf("invalid default %q: overflows %s", tag, f.Type)
+	}
+	return strconv.FormatUint(n, 10), nil
+}
+
+// durationLiteral writes d in the largest unit dividing it, such as
+// 30 * time.Second.
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d != 0 && d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
@@ -0,0 +1,69 @@
+package optiongen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// Marker is the comment selecting a struct for Discover:
+//
+//	//optiongen:options
+//	type Client struct {
+//		...
+//	}
+const Marker = "//optiongen:options"
+
+// Discover returns the names of the structs in the Go package in dir whose
+// doc comment holds Marker, in the order they are declared. A directory
+// without Go files yields none.
+func Discover(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parseDir(fset, dir, notTest, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if _, ok := ts.Type.(*ast.StructType); !ok {
+						continue
+					}
+					// The marker of a single spec may sit on the declaration.
+					doc := ts.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					if marked(doc) {
+						names = append(names, ts.Name.Name)
+					}
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// marked reports whether doc holds Marker on a line of its own. Directives
+// like it are dropped by ast.CommentGroup.Text, so the raw comments are
+// inspected.
+func marked(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == Marker {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package optiongen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go": `package app
+
+//optiongen:options
+type Client struct{}
+
+// Server serves.
+//
+//optiongen:options
+type Server struct{}
+
+type (
+	//optiongen:options
+	Retry struct{}
+
+	Plain struct{}
+)
+
+//optiongen:options
+type Mode int
+`,
+		"b.go": `package app
+
+// Mentions //optiongen:options without being marked.
+type Other struct{}
+
+//optiongen:options
+type Last struct{}
+`,
+		"a_test.go": `package app
+
+//optiongen:options
+type Fixture struct{}
+`,
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Client", "Server", "Retry", "Last"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %q, want %q", got, want)
+	}
+
+	if got, err := Discover(t.TempDir()); err != nil || got != nil {
+		t.Errorf("Discover(empty) = %q, %v, want none", got, err)
+	}
+}
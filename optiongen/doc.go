@@ -0,0 +1,10 @@
+// Package optiongen generates functional options for the fields of a
+// struct. It powers the optiongen command and can be used as a library by
+// tools that want to produce the same output:
+//
+//	s, err := optiongen.Parse(".", "Client")
+//	if err != nil {
+//		return err
+//	}
+//	src, err := optiongen.Generate(s, optiongen.ModeOptions)
+package optiongen
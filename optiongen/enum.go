@@ -0,0 +1,157 @@
+package optiongen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// EnumValue is a constant of the type generated for a field with an enum
+// item, such as FormatJSON for the json value of a format field.
+type EnumValue struct {
+	// Name is the name of the constant.
+	Name string
+	// Value is the value as written in the tag.
+	Value string
+}
+
+// EnumValues returns the constants of the type generated for f.
+func (f Field) EnumValues() []EnumValue {
+	values := make([]EnumValue, len(f.Enum))
+	for i, v := range f.Enum {
+		values[i] = EnumValue{Name: f.EnumType + camel(v), Value: v}
+	}
+	return values
+}
+
+// ParamType returns the type of the parameter of the option for f, the
+// generated type of an enum field or the field type.
+func (f Field) ParamType() string {
+	if f.EnumType != "" {
+		return f.EnumType
+	}
+	return f.Type
+}
+
+// Value returns the expression assigning the parameter of the option to
+// the field, converting the generated type of an enum field.
+func (f Field) Value() string {
+	if f.EnumType != "" {
+		return "string(" + f.Param + ")"
+	}
+	return f.Param
+}
+
+// ParamType returns the type of the parameter of the option for f as
+// referred to by the generated options, see Ref. The types of enum fields
+// are declared next to the options.
+func (s *Struct) ParamType(f Field) (string, error) {
+	if f.EnumType != "" {
+		return f.EnumType, nil
+	}
+	return s.Ref(f.Type)
+}
+
+// Enums returns the fields with an enum item, including those of nested
+// structs, whose types and constants are generated with the options.
+func (s *Struct) Enums() []Field {
+	var result []Field
+	var walk func(fields []Field)
+	walk = func(fields []Field) {
+		for _, f := range fields {
+			if f.EnumType != "" {
+				result = append(result, f)
+			}
+			walk(f.Nested)
+		}
+	}
+	walk(s.Fields)
+	return result
+}
+
+// enum reads the enum item of the option tag, such as enum=json|xml|proto,
+// into f and adds the check rejecting other values. The generated type is
+// named after the option, such as Format for WithFormat.
+func enum(f *Field, item, prefix string) error {
+	if f.Type != "string" {
+		return fmt.Errorf("enum needs a string, got %s", f.Type)
+	}
+	for _, v := range strings.Split(item, "|") {
+		if v = strings.TrimSpace(v); v != "" {
+			f.Enum = append(f.Enum, v)
+		}
+	}
+	if len(f.Enum) == 0 {
+		return fmt.Errorf("enum needs values")
+	}
+	for _, c := range f.Checks {
+		if c.Rule == "url" || c.Rule == "email" {
+			return fmt.Errorf("validate rule %s cannot be combined with enum", c.Rule)
+		}
+	}
+	f.EnumType = prefix + f.Base
+	f.Checks = append(f.Checks, Check{
+		Rule:    "enum",
+		Arg:     strings.Join(f.Enum, " "),
+		Cond:    enumCond(*f),
+		Format:  strings.ReplaceAll(f.Name+" must be one of "+strings.Join(f.Enum, ", "), "%", "%%") + ", got %q",
+		Imports: []string{"fmt"},
+	})
+	return nil
+}
+
+// enumCond returns the condition of the check rejecting values of the enum
+// field f other than its constants.
+func enumCond(f Field) string {
+	values := f.EnumValues()
+	conds := make([]string, len(values))
+	for i, v := range values {
+		conds[i] = f.Param + " != " + v.Name
+	}
+	return strings.Join(conds, " && ")
+}
+
+// PrefixEnums puts prefix in front of the types generated for the enum
+// fields of s and their constants, such as ServerFormat and
+// ServerFormatJSON for the format field of Server, so the types generated
+// for several structs can share a package.
+func (s *Struct) PrefixEnums(prefix string) {
+	var walk func(fields []Field)
+	walk = func(fields []Field) {
+		for i := range fields {
+			f := &fields[i]
+			if f.EnumType != "" {
+				f.EnumType = prefix + f.EnumType
+				for j, c := range f.Checks {
+					if c.Rule == "enum" {
+						f.Checks[j].Cond = enumCond(*f)
+					}
+				}
+			}
+			walk(f.Nested)
+		}
+	}
+	walk(s.Fields)
+}
+
+// initialisms are written in upper case in constant names, such as
+// FormatJSON.
+var initialisms = map[string]bool{
+	"api": true, "csv": true, "dns": true, "grpc": true, "html": true, "http": true, "https": true,
+	"id": true, "ip": true, "json": true, "sql": true, "tcp": true, "tls": true, "udp": true,
+	"uri": true, "url": true, "xml": true, "yaml": true,
+}
+
+// camel turns an enum value such as text-plain into TextPlain.
+func camel(value string) string {
+	words := strings.FieldsFunc(value, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	var b strings.Builder
+	for _, w := range words {
+		if initialisms[strings.ToLower(w)] {
+			b.WriteString(strings.ToUpper(w))
+		} else {
+			b.WriteString(upperFirst(w))
+		}
+	}
+	return b.String()
+}
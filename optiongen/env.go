@@ -0,0 +1,74 @@
+package optiongen
+
+import (
+	"slices"
+	"strings"
+)
+
+// EnvVar is an environment variable read by the function generated by
+// GenerateEnv.
+type EnvVar struct {
+	// Name is the name of the variable, such as MYAPP_BASE_URL. For map
+	// fields it is the prefix of the variables holding the entries, such as
+	// MYAPP_HEADER_.
+	Name string
+	// Option is the option the value is passed to.
+	Option string
+	// Parse is the call parsing the variable v, empty when Value uses v
+	// directly.
+	Parse string
+	// Value is the expression passed to Option, using the parsed value x.
+	Value string
+	// Map reports whether the entries of a map field are read from all
+	// variables starting with Name.
+	Map bool
+	// Var is the variable collecting the entries of a map field.
+	Var string
+}
+
+// EnvFunc returns the name of the function generated by GenerateEnv, such as
+// ClientFromEnv, or FromEnv when the options are generated into another
+// package.
+func (s *Struct) EnvFunc() string {
+	if s.Pkg != "" {
+		return "FromEnv"
+	}
+	return s.Name + "FromEnv"
+}
+
+// EnvVars returns the environment variables read for the options of s,
+// named after EnvPrefix and the keys of the fields leading to them, see
+// bindings for the options covered.
+func (s *Struct) EnvVars() []EnvVar {
+	prefix := strings.TrimSuffix(s.EnvPrefix, "_") + "_"
+	var vars []EnvVar
+	for _, b := range s.bindings() {
+		v := EnvVar{Name: prefix + strings.ToUpper(strings.Join(b.Keys, "_")), Option: b.Field.Option, Parse: b.Parse, Value: b.Value, Map: b.Map, Var: b.Var}
+		if v.Map {
+			v.Name += "_"
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// EnvImports returns the packages needed by the output of GenerateEnv.
+func (s *Struct) EnvImports() []Import {
+	imports := append(slices.Clone(s.ConstraintImports), Import{Name: "errors", Path: "errors"}, Import{Name: "os", Path: "os"})
+	for _, v := range s.EnvVars() {
+		code := v.Parse + v.Value
+		if v.Map {
+			code = "strings."
+		}
+		for _, pkg := range []string{"strconv", "strings", "time"} {
+			if strings.Contains(code, pkg+".") {
+				imports = addImport(imports, Import{Name: pkg, Path: pkg})
+			}
+		}
+		if v.Parse != "" {
+			imports = addImport(imports, Import{Name: "fmt", Path: "fmt"})
+		}
+	}
+	slices.SortFunc(imports, func(a, b Import) int { return strings.Compare(a.Path, b.Path) })
+	return imports
+}
@@ -0,0 +1,89 @@
+package optiongen
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+const (
+	// header starts every generated file.
+	header = "// Code generated by optiongen. DO NOT EDIT."
+	// fingerprintPrefix starts the line after the header recording the
+	// fingerprint of the input.
+	fingerprintPrefix = "// optiongen:fingerprint "
+	// modulePath is the path of the module providing the generator.
+	modulePath = "github.com/StevenCyb/golang-functional-options"
+)
+
+// sources are the Go files of the generator, hashed by Fingerprint so that
+// changes to its logic invalidate the output like changes to its templates.
+//
+//go:embed *.go
+var sources embed.FS
+
+// generator identifies the code of the generator, see generatorHash.
+var generator = sync.OnceValue(generatorHash)
+
+// generatorHash hashes the embedded templates and sources of the generator
+// and the version of the module providing it, as recorded in the build
+// info of the running program.
+func generatorHash() []byte {
+	h := sha256.New()
+	for _, fsys := range []embed.FS{templateFS, sources} {
+		_ = fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			// Tests do not shape the output.
+			if err != nil || d.IsDir() || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			content, err := fsys.ReadFile(path)
+			h.Write([]byte(path))
+			h.Write(content)
+			return err
+		})
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		mods := append([]*debug.Module{&info.Main}, info.Deps...)
+		for _, m := range mods {
+			if m.Path == modulePath && m.Version != "(devel)" {
+				h.Write([]byte(m.Version + m.Sum))
+			}
+		}
+	}
+	return h.Sum(nil)
+}
+
+// Fingerprint hashes everything the output of mode for s depends on: the
+// parsed struct, including the options set on it such as the templates of
+// -templates and the plugins of -plugins, and the generator, that is its
+// templates, its code and its version. The generated files record it, so
+// tools running optiongen in large repositories can skip files whose input
+// has not changed, see Fingerprinted.
+func Fingerprint(s *Struct, mode Mode) string {
+	h := sha256.New()
+	h.Write([]byte(mode))
+	// Struct holds plain data only and always encodes.
+	model, _ := json.Marshal(s)
+	h.Write(model)
+	h.Write(generator())
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// Fingerprinted returns the fingerprint recorded in generated source, or an
+// empty string when there is none.
+func Fingerprinted(src []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for i := 0; i < 2 && scanner.Scan(); i++ {
+		if fingerprint, ok := strings.CutPrefix(scanner.Text(), fingerprintPrefix); ok {
+			return fingerprint
+		}
+	}
+	return ""
+}
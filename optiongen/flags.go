@@ -0,0 +1,108 @@
+package optiongen
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Flag is a command line flag defined by the function generated by
+// GenerateFlags.
+type Flag struct {
+	// Name is the name of the flag, such as retry-max-attempts.
+	Name string
+	// Short is the one letter shorthand of the flag, defined on a
+	// pflag.FlagSet only.
+	Short string
+	// Usage is the help text of the flag, the first line of the field doc.
+	Usage string
+	// Option is the option the value is passed to.
+	Option string
+	// Func is the method of the FlagSet defining the flag, BoolFunc for
+	// booleans and Func otherwise, with a P suffix for the variants taking
+	// a shorthand on a pflag.FlagSet.
+	Func string
+	// Parse is the call parsing the flag value v, empty when Value uses v
+	// directly.
+	Parse string
+	// Value is the expression passed to Option, using the parsed value x.
+	Value string
+	// Map reports whether the flag is repeated to set the entries of a map
+	// field, such as -header Authorization=token.
+	Map bool
+	// Var is the variable collecting the entries of a map field.
+	Var string
+}
+
+// FlagsFunc returns the name of the function generated by GenerateFlags,
+// such as ClientFlags, or Flags when the options are generated into another
+// package.
+func (s *Struct) FlagsFunc() string {
+	if s.Pkg != "" {
+		return "Flags"
+	}
+	return s.Name + "Flags"
+}
+
+// Flags returns the flags defined for the options of s, named after the
+// keys of the fields leading to them in kebab case, see bindings for the
+// options covered.
+func (s *Struct) Flags() []Flag {
+	var flags []Flag
+	for _, b := range s.bindings() {
+		usage, _, _ := strings.Cut(b.Field.Doc, "\n")
+		if usage == "" {
+			usage = "sets the option " + b.Field.Option
+		}
+		f := Flag{
+			Name:   strings.ReplaceAll(strings.Join(b.Keys, "-"), "_", "-"),
+			Short:  b.Field.Short,
+			Usage:  strconv.Quote(usage),
+			Option: b.Field.Option,
+			Func:   "Func",
+			Parse:  b.Parse,
+			Value:  b.Value,
+			Map:    b.Map,
+			Var:    b.Var,
+		}
+		if b.Field.Type == "bool" {
+			f.Func = "BoolFunc"
+		}
+		if s.PFlag {
+			f.Func += "P"
+		}
+		flags = append(flags, f)
+	}
+	return flags
+}
+
+// FlagSet returns the type of the flag set the output of GenerateFlags
+// defines the flags on.
+func (s *Struct) FlagSet() string {
+	if s.PFlag {
+		return "*pflag.FlagSet"
+	}
+	return "*flag.FlagSet"
+}
+
+// FlagImports returns the standard library packages needed by the output of
+// GenerateFlags; pflag is imported next to the options package.
+func (s *Struct) FlagImports() []Import {
+	imports := slices.Clone(s.ConstraintImports)
+	if !s.PFlag {
+		imports = append(imports, Import{Name: "flag", Path: "flag"})
+	}
+	for _, f := range s.Flags() {
+		code := f.Parse + f.Value
+		if f.Map {
+			code = "fmt.strings."
+		}
+		for _, pkg := range []string{"fmt", "strconv", "strings", "time"} {
+			if strings.Contains(code, pkg+".") {
+				imports = addImport(imports, Import{Name: pkg, Path: pkg})
+			}
+		}
+	}
+	slices.SortFunc(imports, func(a, b Import) int { return strings.Compare(a.Path, b.Path) })
+	return imports
+}
@@ -0,0 +1,124 @@
+package optiongen
+
+import (
+	"slices"
+	"strings"
+)
+
+// Fuzz is the fuzz target generated by GenerateFuzz.
+type Fuzz struct {
+	// Params are the inputs of the target, one per option taking a value
+	// the fuzzing engine can produce.
+	Params []FuzzParam
+	// Constructor reports whether the target calls the constructor, which
+	// it only does when every required option is among Params.
+	Constructor bool
+	// Imports are the packages referenced by the conversions and seeds.
+	Imports []Import
+}
+
+// FuzzParam is an input of the fuzz target passed to an option.
+type FuzzParam struct {
+	// Name is the parameter name, such as retryMaxAttempts.
+	Name string
+	// Type is the type of the input, which testing.F supports.
+	Type string
+	// Option is the name of the option.
+	Option string
+	// Convert is the type the input is converted to for the option, such as
+	// time.Duration for an int64 input.
+	Convert string
+	// Seed is the Go expression of a valid input added to the corpus.
+	Seed string
+}
+
+// Arg returns the argument passing p to its option.
+func (p FuzzParam) Arg() string {
+	if p.Convert != "" {
+		return p.Convert + "(" + p.Name + ")"
+	}
+	return p.Name
+}
+
+// Zero returns the Go expression of the zero input of p.
+func (p FuzzParam) Zero() string {
+	switch p.Type {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	case "[]byte":
+		return "[]byte(nil)"
+	}
+	return p.Type + "(0)"
+}
+
+// fuzzTypes are the argument types supported by testing.F, besides
+// []byte.
+var fuzzTypes = []string{"string", "bool", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune", "float32", "float64"}
+
+// Fuzz derives the fuzz target of the options of s. Options of fields whose
+// type the fuzzing engine cannot produce, such as maps and interfaces, are
+// left out.
+func (s *Struct) Fuzz() Fuzz {
+	var z Fuzz
+	used := map[string]bool{}
+	// The names of the generated code.
+	names := map[string]bool{"t": true, "f": true, "opts": true, "got": true, "err": true}
+	passed := map[string]bool{}
+	add := func(f Field, base string) {
+		typ, convert := f.Type, ""
+		switch {
+		case f.Deprecated != "":
+			return
+		case f.EnumType != "":
+			convert = f.EnumType
+		case f.Type == "time.Duration":
+			typ, convert = "int64", f.Type
+		case f.Type != "[]byte" && !slices.Contains(fuzzTypes, f.Type):
+			return
+		}
+		name := paramName(base, s.Receiver)
+		for names[name] {
+			name += "Value"
+		}
+		names[name] = true
+		p := FuzzParam{Name: name, Type: typ, Option: f.Option, Convert: convert}
+		seed, ok := sample(f)
+		switch {
+		case !ok:
+			p.Seed = p.Zero()
+		case typ == "string", typ == "bool", strings.HasPrefix(seed, typ+"("):
+			// The value already has the type of the input.
+			p.Seed = seed
+		default:
+			p.Seed = typ + "(" + seed + ")"
+		}
+		z.Params = append(z.Params, p)
+		passed[f.Option] = true
+		for _, pkg := range f.Packages {
+			used[pkg] = true
+		}
+	}
+	for _, f := range s.Fields {
+		add(f, f.Base)
+	}
+	for _, o := range s.NestedOptions() {
+		base := ""
+		for _, g := range o.Getters {
+			base += g.Base
+		}
+		add(o.Field, base+o.Field.Base)
+	}
+
+	z.Constructor = s.Constructor != ""
+	for _, f := range s.Required() {
+		z.Constructor = z.Constructor && passed[f.Option]
+	}
+	for _, i := range slices.Concat(s.Imports, s.NestedImports) {
+		if used[i.Name] && !slices.Contains(z.Imports, i) {
+			z.Imports = append(z.Imports, i)
+		}
+	}
+	return z
+}
@@ -0,0 +1,409 @@
+package optiongen
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"go/token"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"comment": comment,
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// DefaultDocTemplate is the template for the doc comment of generated
+// options. It is executed with a DocData.
+const DefaultDocTemplate = `{{.Field.Option}} sets the {{.Field.Name}} field of {{.Struct.Name}}.
+{{- with .Field.Doc}}
+
+{{.}}
+{{- end}}`
+
+// DocData is passed to the doc comment template.
+type DocData struct {
+	Struct *Struct
+	Field  Field
+}
+
+// OptionDoc renders the doc comment text of the option generated for f,
+// using DocTemplate or DefaultDocTemplate when it is empty.
+func (s *Struct) OptionDoc(f Field) (string, error) {
+	text := s.DocTemplate
+	if text == "" {
+		text = DefaultDocTemplate
+	}
+	tmpl, err := template.New("doc").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing doc template: %w", err)
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, DocData{Struct: s, Field: f}); err != nil {
+		return "", fmt.Errorf("executing doc template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// comment turns text into a Go line comment.
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Mode selects what Generate produces.
+type Mode string
+
+const (
+	// ModeOptions generates a functional option per field and a constructor.
+	ModeOptions Mode = "options"
+	// ModeSetters generates chainable SetX methods for the fields without a
+	// handwritten one.
+	ModeSetters Mode = "setters"
+	// ModeConfig generates a Config struct that translates into the options
+	// generated by ModeOptions, and a NewWithConfig constructor.
+	ModeConfig Mode = "config"
+	// ModeConstructors generates telescoping NewWithX constructors that
+	// delegate to the constructor taking the options of ModeOptions.
+	ModeConstructors Mode = "constructors"
+	// ModeInterface generates the default implementation of an interface
+	// parsed by ParseInterface. Its options are generated by ModeOptions.
+	ModeInterface Mode = "interface"
+	// ModeBuilder generates a builder with a fluent method per option of
+	// ModeOptions and a Build method creating the struct.
+	ModeBuilder Mode = "builder"
+)
+
+// Modes lists all supported modes.
+var Modes = []Mode{ModeOptions, ModeSetters, ModeConfig, ModeConstructors, ModeInterface, ModeBuilder}
+
+// ModeTests, ModeFuzz, ModeAccessors, ModeDeprecated, ModeEnv, ModeFlags,
+// ModeLoaders, ModeMigrate and ModeRedacted identify the output of the
+// Generate functions of the same name for Fingerprint. They are not accepted
+// by Generate.
+const (
+	ModeTests      Mode = "tests"
+	ModeFuzz       Mode = "fuzz"
+	ModeAccessors  Mode = "accessors"
+	ModeDeprecated Mode = "deprecated"
+	ModeEnv        Mode = "env"
+	ModeFlags      Mode = "flags"
+	ModeLoaders    Mode = "loaders"
+	ModeMigrate    Mode = "migrate"
+	ModeRedacted   Mode = "redacted"
+)
+
+// Generate renders the code selected by mode for s as formatted Go source.
+//
+// The Overrides of s can redefine the following blocks to enforce a house
+// style, such as wrapping errors or logging in every option:
+//
+//	{{define "name"}}{{.Struct.OptionPrefix}}{{.Base}}{{end}}
+//
+// names the option, executed with a NameData. The Base of the NameData is
+// cased according to the Style of s.
+//
+//	{{define "body"}}...{{end}}
+//
+// renders the statements of an option, executed with a BodyData. The
+// default body runs the checks block, which returns the errors of the
+// validate tag, and the set block, which assigns the value to Target and
+// returns nil. With BridgeSetters the set block calls the Setter of the
+// field instead.
+//
+//	{{define "imports"}}"log"{{end}}
+//
+// adds imports needed by the body.
+func Generate(s *Struct, mode Mode) ([]byte, error) {
+	if !slices.Contains(Modes, mode) {
+		return nil, fmt.Errorf("unknown mode %q", mode)
+	}
+	if s.Pkg != "" && mode != ModeOptions {
+		return nil, fmt.Errorf("mode %s cannot be generated into another package", mode)
+	}
+	if mode == ModeInterface && s.Interface == "" {
+		return nil, fmt.Errorf("mode %s needs a struct parsed by ParseInterface", mode)
+	}
+	if mode == ModeConstructors && s.Constructor == "" {
+		return nil, fmt.Errorf("mode %s needs a constructor to delegate to", mode)
+	}
+	return execute(mode, s)
+}
+
+// GenerateAccessors renders the OptionFields methods that give the options
+// generated into the package s.Pkg access to the unexported fields. The
+// output belongs to the package declaring the struct.
+func GenerateAccessors(s *Struct) ([]byte, error) {
+	if s.Pkg == "" {
+		return nil, fmt.Errorf("accessors need the options to be generated into another package")
+	}
+	return execute(ModeAccessors, s)
+}
+
+// GenerateDeprecated renders the options of the deprecated fields, to be
+// written next to the output of ModeOptions. They carry a Deprecated doc
+// comment and call the deprecation hook of the options package when
+// applied, see options.SetDeprecationHook.
+func GenerateDeprecated(s *Struct) ([]byte, error) {
+	if len(s.DeprecatedFields()) == 0 {
+		return nil, fmt.Errorf("struct %s has no deprecated options", s.Name)
+	}
+	return execute(ModeDeprecated, s)
+}
+
+// GenerateEnv renders a function translating the environment variables
+// prefixed with s.EnvPrefix into the options of ModeOptions, to be written
+// next to them. See EnvVars for the variables read.
+func GenerateEnv(s *Struct) ([]byte, error) {
+	if s.EnvPrefix == "" {
+		return nil, fmt.Errorf("environment bindings need a prefix")
+	}
+	if len(s.EnvVars()) == 0 {
+		return nil, fmt.Errorf("struct %s has no options settable from environment variables", s.Name)
+	}
+	return execute(ModeEnv, s)
+}
+
+// GenerateFlags renders a function defining a flag.FlagSet flag per option
+// of ModeOptions, to be written next to them, or a pflag.FlagSet flag with
+// the shorthand of the field when PFlag is set. See Flags for the flags
+// defined.
+func GenerateFlags(s *Struct) ([]byte, error) {
+	flags := s.Flags()
+	if len(flags) == 0 {
+		return nil, fmt.Errorf("struct %s has no options settable from flags", s.Name)
+	}
+	shorts := map[string]string{}
+	for _, f := range flags {
+		if f.Short == "" {
+			continue
+		}
+		if other, ok := shorts[f.Short]; ok {
+			return nil, fmt.Errorf("flags %s and %s share the shorthand %s", other, f.Name, f.Short)
+		}
+		shorts[f.Short] = f.Name
+	}
+	return execute(ModeFlags, s)
+}
+
+// GenerateLoaders renders a function per entry of s.Loaders that decodes a
+// document into the options of ModeOptions, to be written next to them.
+// Documents are decoded into a shadow struct of the options that can be
+// loaded, see Loads, and unknown keys are rejected.
+func GenerateLoaders(s *Struct) ([]byte, error) {
+	if len(s.Loaders) == 0 {
+		return nil, fmt.Errorf("no loaders selected")
+	}
+	for _, l := range s.Loaders {
+		if !slices.Contains(Loaders, l) {
+			return nil, fmt.Errorf("unknown loader %q", l)
+		}
+	}
+	if len(s.Loads()) == 0 {
+		return nil, fmt.Errorf("struct %s has no options that can be loaded from documents", s.Name)
+	}
+	return execute(ModeLoaders, s)
+}
+
+// GenerateMigration renders a deprecated wrapper per constructor of
+// s.Legacy, keeping its signature and passing the parameters to the options
+// of ModeOptions through s.Constructor, which must be exported for the
+// callers of the wrappers to move to it. The output belongs to the package
+// declaring the struct and replaces the originals, see StripLegacy.
+func GenerateMigration(s *Struct) ([]byte, error) {
+	if len(s.Legacy) == 0 {
+		return nil, fmt.Errorf("no legacy constructors selected")
+	}
+	if s.Constructor == "" {
+		return nil, fmt.Errorf("legacy constructors need a constructor to delegate to")
+	}
+	if !token.IsExported(s.Constructor) {
+		return nil, fmt.Errorf("legacy constructors need an exported constructor to point their callers to, not %s", s.Constructor)
+	}
+	if s.TypeParams != "" {
+		return nil, fmt.Errorf("migration of generic struct %s is not supported", s.Name)
+	}
+	if s.Pkg != "" {
+		return nil, fmt.Errorf("migration of options generated into another package is not supported")
+	}
+	return execute(ModeMigrate, s)
+}
+
+// GenerateRedacted renders String and LogValue methods for the struct and
+// the nested structs holding fields tagged `option:"sensitive"`, masking
+// those fields when the struct is printed or logged. The output belongs to
+// the package declaring the struct.
+func GenerateRedacted(s *Struct) ([]byte, error) {
+	if !s.Sensitive() {
+		return nil, fmt.Errorf("struct %s has no sensitive fields", s.Name)
+	}
+	return execute(ModeRedacted, s)
+}
+
+// GenerateTests renders table driven tests for the output of ModeOptions,
+// to be written to a _test.go file next to it. The tests check that every
+// option sets its field, that values rejected by the validate tag fail and
+// that the constructor insists on the required options.
+func GenerateTests(s *Struct) ([]byte, error) {
+	if s.TypeParams != "" {
+		return nil, fmt.Errorf("tests for generic struct %s are not supported", s.Name)
+	}
+	if s.Pkg != "" {
+		return nil, fmt.Errorf("tests for options generated into another package are not supported")
+	}
+	return execute(ModeTests, s)
+}
+
+// GenerateFuzz renders a fuzz target for the output of ModeOptions, to be
+// written to a _test.go file next to it. The target passes the inputs of
+// the fuzzing engine to the options, see Fuzz, and applies them as well as
+// the constructor, so values making an option or validator panic are found
+// by go test -fuzz.
+func GenerateFuzz(s *Struct) ([]byte, error) {
+	if s.TypeParams != "" {
+		return nil, fmt.Errorf("fuzz targets for generic struct %s are not supported", s.Name)
+	}
+	if s.Pkg != "" {
+		return nil, fmt.Errorf("fuzz targets for options generated into another package are not supported")
+	}
+	if len(s.Fuzz().Params) == 0 {
+		return nil, fmt.Errorf("struct %s has no options taking values the fuzzing engine can produce", s.Name)
+	}
+	return execute(ModeFuzz, s)
+}
+
+// NameData is passed to the name block.
+type NameData struct {
+	Struct *Struct
+	Field  Field
+	// Base is the name the option is derived from, including the names of
+	// the fields holding a nested struct, such as RetryMaxAttempts.
+	Base string
+}
+
+// BodyData is passed to the body block.
+type BodyData struct {
+	Struct *Struct
+	Field  Field
+	// Receiver is the variable holding the struct declaring Field.
+	Receiver string
+	// Target is the expression assigning Field, such as c.baseURL.
+	Target string
+}
+
+// Body returns the data of the body block for the option of f.
+func (s *Struct) Body(f Field, receiver, target string) BodyData {
+	return BodyData{Struct: s, Field: f, Receiver: receiver, Target: target}
+}
+
+// execute renders the template of mode for s as formatted Go source, stamped
+// with the fingerprint of the input.
+func execute(mode Mode, s *Struct) ([]byte, error) {
+	fingerprint := Fingerprint(s, mode)
+	set, err := s.templateSet()
+	if err != nil {
+		return nil, err
+	}
+	if s, err = s.renamed(set); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := set.ExecuteTemplate(&buf, string(mode)+".tmpl", s); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	stamp := header + "\n" + fingerprintPrefix + fingerprint + "\n"
+	return bytes.Replace(src, []byte(header+"\n"), []byte(stamp), 1), nil
+}
+
+// templateSet returns the templates of the generated code with the blocks
+// redefined by the Overrides of s.
+func (s *Struct) templateSet() (*template.Template, error) {
+	if s.Overrides == "" {
+		return templates, nil
+	}
+	set, err := templates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := set.New("overrides").Parse(s.Overrides); err != nil {
+		return nil, fmt.Errorf("parsing overrides: %w", err)
+	}
+	return set, nil
+}
+
+// renamed returns a copy of s with the options named by the name block,
+// except those named by the func item of the option tag. The options adding
+// a single element and those of delegates follow the Prefix and Style of s.
+func (s *Struct) renamed(set *template.Template) (*Struct, error) {
+	var rename func(fields []Field, prefix string) ([]Field, error)
+	rename = func(fields []Field, prefix string) ([]Field, error) {
+		fields = slices.Clone(fields)
+		for i, f := range fields {
+			base, err := s.styled(prefix + f.Base)
+			if err != nil {
+				return nil, err
+			}
+			if f.Func != "" {
+				fields[i].Option = f.Func
+			} else {
+				var buf strings.Builder
+				if err := set.ExecuteTemplate(&buf, "name", NameData{Struct: s, Field: f, Base: base}); err != nil {
+					return nil, err
+				}
+				fields[i].Option = strings.TrimSpace(buf.String())
+			}
+			if f.Item != "" {
+				if fields[i].Item, err = s.optionName(f.Item); err != nil {
+					return nil, err
+				}
+			}
+			nestedPrefix := prefix + f.Base
+			if f.Embedded {
+				nestedPrefix = prefix
+			}
+			nested, err := rename(f.Nested, nestedPrefix)
+			if err != nil {
+				return nil, err
+			}
+			fields[i].Nested = nested
+		}
+		return fields, nil
+	}
+
+	renamed := *s
+	fields, err := rename(s.Fields, "")
+	if err != nil {
+		return nil, err
+	}
+	renamed.Fields = fields
+	renamed.Delegates = slices.Clone(s.Delegates)
+	for i, d := range renamed.Delegates {
+		if renamed.Delegates[i].Option, err = s.optionName(d.Option); err != nil {
+			return nil, err
+		}
+	}
+	renamed.Legacy = slices.Clone(s.Legacy)
+	for i, l := range renamed.Legacy {
+		l.Params = slices.Clone(l.Params)
+		for j, p := range l.Params {
+			if k := slices.IndexFunc(fields, func(f Field) bool { return f.Name == p.Field }); k >= 0 {
+				l.Params[j].Option = fields[k].Option
+			}
+		}
+		renamed.Legacy[i] = l
+	}
+	return &renamed, nil
+}
@@ -0,0 +1,86 @@
+package optiongen
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestGenerateGolden(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir, typ string
+		parse    func(dir, typeName string) (*Struct, error)
+		setup    func(*Struct)
+		generate func(*Struct) ([]byte, error)
+	}{
+		{name: "options", dir: "client", typ: "Client", generate: mode(ModeOptions)},
+		{name: "setters", dir: "client", typ: "Client", generate: mode(ModeSetters)},
+		{name: "config", dir: "client", typ: "Client", generate: mode(ModeConfig)},
+		{name: "constructors", dir: "client", typ: "Client", generate: mode(ModeConstructors)},
+		{name: "interface", dir: "logger", typ: "Logger", parse: ParseInterface, generate: mode(ModeInterface)},
+		{name: "builder", dir: "client", typ: "Client", generate: mode(ModeBuilder)},
+		{name: "pkg", dir: "client", typ: "Client", setup: intoPkg, generate: mode(ModeOptions)},
+		{name: "tests", dir: "client", typ: "Client", generate: GenerateTests},
+		{name: "fuzz", dir: "client", typ: "Client", generate: GenerateFuzz},
+		{name: "accessors", dir: "client", typ: "Client", setup: intoPkg, generate: GenerateAccessors},
+		{name: "deprecated", dir: "client", typ: "Client", generate: GenerateDeprecated},
+		{name: "env", dir: "client", typ: "Client", setup: func(s *Struct) { s.EnvPrefix = "CLIENT" }, generate: GenerateEnv},
+		{name: "flags", dir: "client", typ: "Client", generate: GenerateFlags},
+		{name: "pflag", dir: "client", typ: "Client", setup: func(s *Struct) { s.PFlag = true }, generate: GenerateFlags},
+		{name: "loaders", dir: "client", typ: "Client", setup: func(s *Struct) { s.Loaders = []Loader{LoaderJSON, LoaderYAML} }, generate: GenerateLoaders},
+		{name: "redacted", dir: "client", typ: "Client", generate: GenerateRedacted},
+		{name: "loaders", dir: "limits", typ: "Limits", setup: func(s *Struct) { s.Loaders = []Loader{LoaderJSON} }, generate: GenerateLoaders},
+		{name: "bridge", dir: "bridge", typ: "Store", setup: func(s *Struct) { s.BridgeSetters = true }, generate: mode(ModeOptions)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parse := tt.parse
+			if parse == nil {
+				parse = Parse
+			}
+			s, err := parse(filepath.Join("testdata", tt.dir), tt.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+			got, err := tt.generate(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The fingerprint changes with the code of the generator, the
+			// golden files cover the output only.
+			got = bytes.Replace(got, []byte(fingerprintPrefix+Fingerprinted(got)+"\n"), nil, 1)
+			golden := filepath.Join("testdata", tt.dir, tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s output differs from %s, rerun with -update after checking the change:\n%s", tt.name, golden, got)
+			}
+		})
+	}
+}
+
+// intoPkg generates the options into a sub-package of the struct.
+func intoPkg(s *Struct) {
+	s.Pkg = "clientopts"
+	s.ImportPath = "example.com/client"
+}
+
+// mode returns the generator of the output mode m.
+func mode(m Mode) func(*Struct) ([]byte, error) {
+	return func(s *Struct) ([]byte, error) { return Generate(s, m) }
+}
@@ -0,0 +1,179 @@
+package optiongen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// Method is a method of an interface that ParseInterface derives a default
+// implementation from.
+type Method struct {
+	// Name is the name of the method.
+	Name string
+	// Doc is the doc comment of the method.
+	Doc string
+	// Params is the parameter list with every parameter named, such as
+	// msg string, args ...any.
+	Params string
+	// Args passes the parameters on, such as msg, args...
+	Args string
+	// Results is the result list with every result named, such as
+	// (n int, err error), or empty for a method without results.
+	Results string
+	// Field is the struct field holding the function implementing the
+	// method.
+	Field string
+}
+
+// ParseInterface reads the Go package in dir and returns the model of a
+// default implementation of the interface called typeName, for ModeInterface.
+// The implementation delegates every method to a function field configured
+// by an option, such as WithLoggerInfo for the Info method of ILogger, and
+// does nothing and returns zero values while the field is nil.
+func ParseInterface(dir, typeName string) (*Struct, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parseDir(fset, dir, notTest, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			if spec, it := findInterface(file, typeName); it != nil {
+				return newInterface(fset, file, spec, it)
+			}
+		}
+	}
+	return nil, fmt.Errorf("interface %s not found in %s", typeName, dir)
+}
+
+func findInterface(file *ast.File, typeName string) (*ast.TypeSpec, *ast.InterfaceType) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != typeName {
+				continue
+			}
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				return ts, it
+			}
+		}
+	}
+	return nil, nil
+}
+
+func newInterface(fset *token.FileSet, file *ast.File, spec *ast.TypeSpec, it *ast.InterfaceType) (*Struct, error) {
+	if spec.TypeParams != nil {
+		return nil, fmt.Errorf("generic interface %s is not supported", spec.Name.Name)
+	}
+	base := implBase(spec.Name.Name)
+	name := "Default" + base
+	s := &Struct{
+		Package:     file.Name.Name,
+		Name:        name,
+		Interface:   spec.Name.Name,
+		Receiver:    receiverName(name),
+		Constructor: "New" + name,
+	}
+
+	used := map[string]bool{}
+	for _, m := range it.Methods.List {
+		ft, ok := m.Type.(*ast.FuncType)
+		if !ok || len(m.Names) == 0 {
+			return nil, fmt.Errorf("interface %s embeds %s, which is not supported", spec.Name.Name, mustString(fset, m.Type))
+		}
+		typ, err := exprString(fset, ft)
+		if err != nil {
+			return nil, err
+		}
+		packages := map[string]bool{}
+		collectPackages(ft, packages)
+		var pkgNames []string
+		for pkg := range packages {
+			used[pkg] = true
+			pkgNames = append(pkgNames, pkg)
+		}
+		sort.Strings(pkgNames)
+
+		method := Method{Name: m.Names[0].Name, Doc: fieldDoc(m), Field: lowerFirst(m.Names[0].Name)}
+		if method.Params, method.Args, err = params(fset, ft.Params, "p", s.Receiver); err != nil {
+			return nil, err
+		}
+		if ft.Results != nil {
+			results, _, err := params(fset, ft.Results, "r", s.Receiver)
+			if err != nil {
+				return nil, err
+			}
+			method.Results = "(" + results + ")"
+		}
+		s.Methods = append(s.Methods, method)
+		s.Fields = append(s.Fields, Field{
+			Name:     method.Field,
+			Type:     typ,
+			Doc:      method.Doc,
+			Base:     base + method.Name,
+			Option:   "With" + base + method.Name,
+			Param:    paramName(method.Field, s.Receiver),
+			Packages: pkgNames,
+		})
+	}
+
+	imports, err := resolveImports(file, used)
+	if err != nil {
+		return nil, err
+	}
+	s.Imports = imports
+	return s, nil
+}
+
+// params renders list with every entry named, naming unnamed ones after
+// prefix and their position, and the arguments passing them on. Names
+// shadowing the receiver are renamed.
+func params(fset *token.FileSet, list *ast.FieldList, prefix, receiver string) (decl, args string, err error) {
+	var decls, names []string
+	for _, field := range list.List {
+		typ, err := exprString(fset, field.Type)
+		if err != nil {
+			return "", "", err
+		}
+		fieldNames := field.Names
+		if len(fieldNames) == 0 {
+			fieldNames = []*ast.Ident{{Name: "_"}}
+		}
+		for _, id := range fieldNames {
+			name := id.Name
+			if name == "_" {
+				name = fmt.Sprintf("%s%d", prefix, len(names))
+			} else if name == receiver {
+				name += "Value"
+			}
+			decls = append(decls, name+" "+typ)
+			if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+				name += "..."
+			}
+			names = append(names, name)
+		}
+	}
+	return strings.Join(decls, ", "), strings.Join(names, ", "), nil
+}
+
+// implBase strips the I prefix of interface names such as ILogger.
+func implBase(name string) string {
+	if len(name) > 1 && name[0] == 'I' && strings.ToUpper(name[1:2]) == name[1:2] {
+		return name[1:]
+	}
+	return upperFirst(name)
+}
+
+func mustString(fset *token.FileSet, expr ast.Expr) string {
+	s, _ := exprString(fset, expr)
+	return s
+}
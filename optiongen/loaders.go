@@ -0,0 +1,168 @@
+package optiongen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Loader selects a document format read by the functions generated by
+// GenerateLoaders.
+type Loader string
+
+const (
+	// LoaderJSON generates <Type>FromJSON using encoding/json.
+	LoaderJSON Loader = "json"
+	// LoaderYAML generates <Type>FromYAML using gopkg.in/yaml.v3, which the
+	// module of the generated code has to require.
+	LoaderYAML Loader = "yaml"
+)
+
+// Loaders lists all supported loaders.
+var Loaders = []Loader{LoaderJSON, LoaderYAML}
+
+// Load is a document key translated into an option by the functions
+// generated by GenerateLoaders.
+type Load struct {
+	// Key is the dotted path of the key, such as retry.max_attempts.
+	Key string
+	// Selector selects the value in the document struct, such as
+	// Retry.MaxAttempts.
+	Selector string
+	// Option is the option the value is passed to.
+	Option string
+	// Pointer reports whether the document holds a pointer to the value,
+	// which is nil for absent keys. Maps and slices are held as they are.
+	Pointer bool
+	// Duration reports whether the value is a duration string such as
+	// 100ms.
+	Duration bool
+	// Parse is the function parsing the string held by the document for
+	// durations and sizes, such as options.ParseSize[int64], and empty for
+	// values decoded as they are.
+	Parse string
+	// Value is the expression passed to Option for values that are not
+	// parsed.
+	Value string
+}
+
+// LoaderFunc returns the name of the function generated for l, such as
+// ClientFromJSON, or FromJSON when the options are generated into another
+// package.
+func (s *Struct) LoaderFunc(l Loader) string {
+	name := "From" + strings.ToUpper(string(l))
+	if s.Pkg != "" {
+		return name
+	}
+	return s.Name + name
+}
+
+// DocumentType returns the name of the generated struct the documents are
+// decoded into, such as clientDocument.
+func (s *Struct) DocumentType() string {
+	return lowerFirst(s.Name) + "Document"
+}
+
+// Loads returns the keys read from documents for the options of s, see
+// bindings for the options covered.
+func (s *Struct) Loads() []Load {
+	var loads []Load
+	for _, b := range s.bindings() {
+		collection := b.Map || strings.HasPrefix(b.Field.Type, "[]")
+		load := Load{
+			Key:      strings.Join(b.Keys, "."),
+			Selector: strings.Join(b.Bases, "."),
+			Option:   b.Field.Option,
+			Pointer:  !collection,
+			Duration: b.Field.Type == "time.Duration",
+			Value:    "doc." + strings.Join(b.Bases, "."),
+		}
+		if load.Pointer {
+			load.Value = "*" + load.Value
+		}
+		if b.Field.EnumType != "" {
+			load.Value = b.Field.EnumType + "(" + load.Value + ")"
+		}
+		switch {
+		case load.Duration:
+			load.Parse = "time.ParseDuration"
+		case b.Field.Size:
+			// size only accepts identifiers, which Ref qualifies without
+			// failing.
+			typ, _ := s.Ref(b.Field.Type)
+			load.Parse = "options.ParseSize[" + typ + "]"
+		}
+		loads = append(loads, load)
+	}
+	return loads
+}
+
+// Document returns the struct type literal the documents are decoded into.
+// Nested structs become nested struct types, fields are pointers so absent
+// keys can be told apart from zero values, and durations and sizes are
+// read as strings.
+func (s *Struct) Document() string {
+	type node struct {
+		base, key, typ string
+		children       []*node
+	}
+	root := &node{}
+	for _, b := range s.bindings() {
+		parent := root
+		for i, key := range b.Keys[:len(b.Keys)-1] {
+			idx := slices.IndexFunc(parent.children, func(n *node) bool { return n.key == key })
+			if idx < 0 {
+				parent.children = append(parent.children, &node{base: b.Bases[i], key: key})
+				idx = len(parent.children) - 1
+			}
+			parent = parent.children[idx]
+		}
+		typ := b.Field.Type
+		if typ == "time.Duration" || b.Field.Size {
+			typ = "string"
+		}
+		if !b.Map && !strings.HasPrefix(typ, "[]") {
+			typ = "*" + typ
+		}
+		parent.children = append(parent.children, &node{base: b.Bases[len(b.Bases)-1], key: b.Keys[len(b.Keys)-1], typ: typ})
+	}
+
+	var render func(n *node) string
+	render = func(n *node) string {
+		var b strings.Builder
+		b.WriteString("struct {\n")
+		for _, c := range n.children {
+			typ := c.typ
+			if typ == "" {
+				typ = render(c)
+			}
+			fmt.Fprintf(&b, "%s %s `json:%q yaml:%q`\n", c.base, typ, c.key, c.key)
+		}
+		b.WriteString("}")
+		return b.String()
+	}
+	return render(root)
+}
+
+// HasLoader reports whether l is among the Loaders of s.
+func (s *Struct) HasLoader(l Loader) bool {
+	return slices.Contains(s.Loaders, l)
+}
+
+// LoaderImports returns the standard library packages needed by the output
+// of GenerateLoaders.
+func (s *Struct) LoaderImports() []Import {
+	imports := append(slices.Clone(s.ConstraintImports), Import{Name: "bytes", Path: "bytes"})
+	if s.HasLoader(LoaderJSON) {
+		imports = append(imports, Import{Name: "json", Path: "encoding/json"})
+	}
+	loads := s.Loads()
+	if slices.ContainsFunc(loads, func(l Load) bool { return l.Parse != "" }) {
+		imports = append(imports, Import{Name: "errors", Path: "errors"}, Import{Name: "fmt", Path: "fmt"})
+	}
+	if slices.ContainsFunc(loads, func(l Load) bool { return l.Duration }) {
+		imports = append(imports, Import{Name: "time", Path: "time"})
+	}
+	slices.SortFunc(imports, func(a, b Import) int { return strings.Compare(a.Path, b.Path) })
+	return imports
+}
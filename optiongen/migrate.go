@@ -0,0 +1,369 @@
+package optiongen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Legacy is a handwritten constructor taking positional parameters, such as
+// NewWithBaseURLAndHeaders(baseURL string, header map[string]string). The
+// output of GenerateMigration replaces it with a deprecated wrapper of the
+// same signature that passes the parameters to the options of ModeOptions.
+type Legacy struct {
+	// Name is the name of the constructor.
+	Name string
+	// Doc is its doc comment, without a Deprecated paragraph.
+	Doc string
+	// Params are its parameters in order.
+	Params []LegacyParam
+	// Value reports whether it returns the struct rather than a pointer.
+	Value bool
+	// Error reports whether it returns an error as well, which FindLegacy
+	// requires.
+	Error bool
+	// Imports are the packages referred to by the parameter types.
+	Imports []Import
+	// File is the file declaring the constructor. Start and End are the
+	// offsets of the declaration in it, including the doc comment.
+	File       string `json:"-"`
+	Start, End int    `json:"-"`
+}
+
+// LegacyParam is a parameter of a Legacy constructor.
+type LegacyParam struct {
+	Name string
+	// Type is the type as written in the source, such as ...string.
+	Type string
+	// Field is the name of the field the parameter is passed to.
+	Field string
+	// Option is the option of Field, set by the generator.
+	Option string
+	// Convert is the type the parameter is converted to for the option,
+	// the EnumType of Field.
+	Convert string
+}
+
+// Arg returns the argument passing p to its option.
+func (p LegacyParam) Arg() string {
+	if p.Convert != "" {
+		return p.Convert + "(" + p.Name + ")"
+	}
+	return p.Name
+}
+
+// Signature returns the parameter list of l as written in the source.
+func (l Legacy) Signature() string {
+	params := make([]string, len(l.Params))
+	for i, p := range l.Params {
+		params[i] = p.Name + " " + p.Type
+	}
+	return strings.Join(params, ", ")
+}
+
+// Options lists the options the parameters of l are passed to, such as
+// WithBaseURL and WithHeaders.
+func (l Legacy) Options() string {
+	opts := make([]string, len(l.Params))
+	for i, p := range l.Params {
+		opts[i] = p.Option
+	}
+	switch len(opts) {
+	case 0:
+		return "no options"
+	case 1:
+		return opts[0]
+	}
+	return strings.Join(opts[:len(opts)-1], ", ") + " and " + opts[len(opts)-1]
+}
+
+// MigrationImports returns the imports of the output of GenerateMigration.
+func (s *Struct) MigrationImports() []Import {
+	var imports []Import
+	for _, l := range s.Legacy {
+		for _, i := range l.Imports {
+			imports = addImport(imports, i)
+		}
+	}
+	return imports
+}
+
+// FindLegacy reads the constructors called names from the Go package in dir
+// into s.Legacy. Each parameter is matched to a top-level field of s by
+// name, ignoring case, or else by being the only remaining field of its
+// type. Constructors must return the struct or a pointer to it followed by
+// an error, as the options can fail, and do nothing but assign their
+// parameters to those fields, as any other setup would be lost.
+//
+// The wrappers written by GenerateMigration are found as well, so the
+// migration can be generated again after the originals were removed.
+func FindLegacy(dir string, s *Struct, names ...string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parseDir(fset, dir, notTest, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(pkgs, func(p *goPackage) bool { return p.Name == s.Package })
+	if i < 0 {
+		return fmt.Errorf("package %s not found in %s", s.Package, dir)
+	}
+	pkg := pkgs[i]
+
+	found := map[string]Legacy{}
+	for j, file := range pkg.Files {
+		path := pkg.Paths[j]
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !slices.Contains(names, fn.Name.Name) {
+				continue
+			}
+			l, err := s.legacy(fset, file, fn)
+			if err != nil {
+				return fmt.Errorf("%s: %w", fn.Name.Name, err)
+			}
+			l.File = path
+			found[l.Name] = l
+		}
+	}
+
+	s.Legacy = nil
+	for _, name := range names {
+		l, ok := found[name]
+		if !ok {
+			return fmt.Errorf("constructor %s not found in %s", name, dir)
+		}
+		if name == s.Constructor {
+			return fmt.Errorf("constructor %s is the constructor the options are generated for", name)
+		}
+		s.Legacy = append(s.Legacy, l)
+	}
+	return nil
+}
+
+// legacy returns the model of the constructor fn.
+func (s *Struct) legacy(fset *token.FileSet, file *ast.File, fn *ast.FuncDecl) (Legacy, error) {
+	if fn.Type.TypeParams != nil {
+		return Legacy{}, fmt.Errorf("generic constructors are not supported")
+	}
+	l := Legacy{Name: fn.Name.Name, Start: fset.Position(fn.Pos()).Offset, End: fset.Position(fn.End()).Offset}
+	if fn.Doc != nil {
+		l.Start = fset.Position(fn.Doc.Pos()).Offset
+		doc, _, _ := strings.Cut(fn.Doc.Text(), "Deprecated: ")
+		l.Doc = strings.TrimSpace(doc)
+	}
+
+	var results []ast.Expr
+	if fn.Type.Results != nil {
+		for _, r := range fn.Type.Results.List {
+			for range max(1, len(r.Names)) {
+				results = append(results, r.Type)
+			}
+		}
+	}
+	if n := len(results); n == 2 {
+		id, ok := results[1].(*ast.Ident)
+		l.Error = ok && id.Name == "error"
+	}
+	if len(results) != 2 || !l.Error {
+		// The options the wrapper passes the parameters to can fail, which
+		// a constructor without an error result has no way to report.
+		return Legacy{}, fmt.Errorf("want results (*%s, error), add the error result before migrating", s.Name)
+	}
+	result := results[0]
+	if star, ok := result.(*ast.StarExpr); ok {
+		result = star.X
+	} else {
+		l.Value = true
+	}
+	if id, ok := result.(*ast.Ident); !ok || id.Name != s.Name {
+		return Legacy{}, fmt.Errorf("want results (*%s, error)", s.Name)
+	}
+
+	used := map[string]bool{}
+	taken := map[string]bool{}
+	for _, param := range fn.Type.Params.List {
+		typ, err := exprString(fset, param.Type)
+		if err != nil {
+			return Legacy{}, err
+		}
+		collectPackages(param.Type, used)
+		if len(param.Names) == 0 {
+			return Legacy{}, fmt.Errorf("unnamed parameters are not supported")
+		}
+		for _, name := range param.Names {
+			f, ok := s.legacyField(name.Name, strings.Replace(typ, "...", "[]", 1), taken)
+			if !ok {
+				return Legacy{}, fmt.Errorf("parameter %s %s matches no field of %s", name.Name, typ, s.Name)
+			}
+			taken[f.Name] = true
+			l.Params = append(l.Params, LegacyParam{Name: name.Name, Type: typ, Field: f.Name, Convert: f.EnumType})
+		}
+	}
+	if !ast.IsGenerated(file) {
+		if err := s.assignsOnly(fset, fn, l.Params); err != nil {
+			return Legacy{}, err
+		}
+	}
+	imports, err := resolveImports(file, used)
+	if err != nil {
+		return Legacy{}, err
+	}
+	l.Imports = imports
+	return l, nil
+}
+
+// assignsOnly makes sure the body of fn is a single return of a literal of
+// s that assigns params to their fields and a nil error, so the wrapper
+// replacing fn loses no setup such as baseClient: &http.Client{}.
+func (s *Struct) assignsOnly(fset *token.FileSet, fn *ast.FuncDecl, params []LegacyParam) error {
+	var ret *ast.ReturnStmt
+	if fn.Body != nil && len(fn.Body.List) == 1 {
+		ret, _ = fn.Body.List[0].(*ast.ReturnStmt)
+	}
+	if ret == nil || len(ret.Results) != 2 {
+		return fmt.Errorf("want a body returning a %s literal of the parameters and a nil error", s.Name)
+	}
+	if id, ok := ret.Results[1].(*ast.Ident); !ok || id.Name != "nil" {
+		return fmt.Errorf("want a body returning a %s literal of the parameters and a nil error", s.Name)
+	}
+	result := ret.Results[0]
+	if u, ok := result.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		result = u.X
+	}
+	lit, ok := result.(*ast.CompositeLit)
+	if !ok {
+		return fmt.Errorf("want a body returning a %s literal of the parameters and a nil error", s.Name)
+	}
+	if id, ok := lit.Type.(*ast.Ident); !ok || id.Name != s.Name {
+		return fmt.Errorf("want a body returning a %s literal of the parameters and a nil error", s.Name)
+	}
+
+	fieldOf := map[string]string{}
+	for _, p := range params {
+		fieldOf[p.Name] = p.Field
+	}
+	var extra []string
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return fmt.Errorf("want a %s literal with field names", s.Name)
+		}
+		key := kv.Key.(*ast.Ident).Name
+		if id, ok := kv.Value.(*ast.Ident); ok {
+			if field, ok := fieldOf[id.Name]; ok {
+				if field != key {
+					return fmt.Errorf("parameter %s is assigned to %s but matches %s", id.Name, key, field)
+				}
+				delete(fieldOf, id.Name)
+				continue
+			}
+		}
+		value, err := exprString(fset, kv.Value)
+		if err != nil {
+			return err
+		}
+		extra = append(extra, key+": "+value)
+	}
+	if len(extra) > 0 {
+		return fmt.Errorf("it sets more than its parameters (%s), move that setup into default tags of the fields and out of the constructor first", strings.Join(extra, ", "))
+	}
+	for _, p := range params {
+		if _, ok := fieldOf[p.Name]; ok {
+			return fmt.Errorf("parameter %s is not assigned to %s", p.Name, p.Field)
+		}
+	}
+	return nil
+}
+
+// legacyField returns the field a parameter of type typ is passed to: the
+// one called name, or else the only field of type typ not yet taken.
+func (s *Struct) legacyField(name, typ string, taken map[string]bool) (Field, bool) {
+	var match []Field
+	for _, f := range s.Fields {
+		if taken[f.Name] || f.Type != typ {
+			continue
+		}
+		if strings.EqualFold(f.Name, name) || strings.EqualFold(f.Base, name) {
+			return f, true
+		}
+		match = append(match, f)
+	}
+	if len(match) != 1 {
+		return Field{}, false
+	}
+	return match[0], true
+}
+
+// StripLegacy removes the declarations of the constructors of legacy from
+// src, the content of the file at path, along with the imports only they
+// used, and returns the formatted result.
+func StripLegacy(path string, src []byte, legacy []Legacy) ([]byte, error) {
+	var spans [][2]int
+	for _, l := range legacy {
+		if l.File == path {
+			spans = append(spans, [2]int{l.Start, l.End})
+		}
+	}
+	if len(spans) == 0 {
+		return src, nil
+	}
+	src = cut(src, spans)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	spans = nil
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		var unused [][2]int
+		for _, spec := range gen.Specs {
+			imp := spec.(*ast.ImportSpec)
+			path := strings.Trim(imp.Path.Value, "\"`")
+			name := guessPackageName(path)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name != "_" && name != "." && !used[name] {
+				unused = append(unused, [2]int{fset.Position(imp.Pos()).Offset, fset.Position(imp.End()).Offset})
+			}
+		}
+		if len(unused) == len(gen.Specs) {
+			unused = [][2]int{{fset.Position(gen.Pos()).Offset, fset.Position(gen.End()).Offset}}
+		}
+		spans = append(spans, unused...)
+	}
+	return format.Source(cut(src, spans))
+}
+
+// cut removes the byte ranges of spans from src.
+func cut(src []byte, spans [][2]int) []byte {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	var buf bytes.Buffer
+	last := 0
+	for _, span := range spans {
+		buf.Write(src[last:span[0]])
+		last = span[1]
+	}
+	buf.Write(src[last:])
+	return buf.Bytes()
+}
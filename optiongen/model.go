@@ -0,0 +1,517 @@
+package optiongen
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Struct is the parsed model of a struct that options are generated for.
+type Struct struct {
+	// Package is the name of the package declaring the struct.
+	Package string
+	// Pkg is the name of the package the options are generated into, empty
+	// for the package declaring the struct.
+	Pkg string
+	// ImportPath is the import path of the package declaring the struct,
+	// needed when Pkg is set.
+	ImportPath string
+	// Name is the name of the struct type.
+	Name string
+	// TypeParams is the type parameter list of a generic struct as written
+	// in the source, such as [K comparable, V any].
+	TypeParams string
+	// TypeArgs lists the type parameters as arguments, such as [K, V].
+	TypeArgs string
+	// Receiver is the variable name the generated code uses for the struct.
+	Receiver string
+	// Constructor is the name of the generated constructor, empty when no
+	// constructor is generated.
+	Constructor string
+	// DocTemplate overrides DefaultDocTemplate for the doc comments of the
+	// generated options.
+	DocTemplate string
+	// Overrides are text/template definitions replacing the blocks of the
+	// generated code, see Generate.
+	Overrides string
+	// Fields are the fields options are generated for.
+	Fields []Field
+	// Delegates are the embedded fields tagged `option:"delegate"`. Each
+	// gets an option applying the options generated for the embedded type,
+	// such as WithBaseConfig(opts ...options.OptionE[BaseConfig]).
+	Delegates []Field
+	// Interface is the interface implemented by the struct, set by
+	// ParseInterface.
+	Interface string
+	// Methods are the methods of Interface.
+	Methods []Method
+	// EnvPrefix is the prefix of the environment variables read by the
+	// output of GenerateEnv, such as MYAPP.
+	EnvPrefix string
+	// PFlag makes the output of GenerateFlags define the flags on a
+	// github.com/spf13/pflag FlagSet, as used by cobra commands, instead of
+	// a flag.FlagSet.
+	PFlag bool
+	// Loaders are the document formats read by the output of
+	// GenerateLoaders.
+	Loaders []Loader
+	// Plugins are the names of the output plugins run for the struct, see
+	// RunPlugin. They are part of the model so that the fingerprint changes
+	// with them.
+	Plugins []string
+	// Prefix replaces With as the prefix of the generated options, such as
+	// Opt for OptTimeout.
+	Prefix string
+	// Style is the casing of the generated options, StyleCamel when empty.
+	Style Style
+	// BridgeSetters makes the options of fields with a Setter call it
+	// instead of assigning the field.
+	BridgeSetters bool
+	// Legacy are the handwritten constructors replaced by the output of
+	// GenerateMigration, see FindLegacy.
+	Legacy []Legacy
+	// Imports are the packages referenced by the field types.
+	Imports []Import
+	// ConstraintImports are the packages referenced by the constraints of
+	// the type parameters.
+	ConstraintImports []Import
+	// NestedImports are the packages referenced by the field types of
+	// nested structs declared in other files.
+	NestedImports []Import
+}
+
+// Field is a struct field that an option is generated for.
+type Field struct {
+	// Name is the name of the field.
+	Name string
+	// Type is the type expression of the field as written in the source.
+	Type string
+	// Doc is the doc comment of the field.
+	Doc string
+	// Key is the key of the field from the key item of the option tag,
+	// defaulting to the snake case field name such as base_url.
+	Key string
+	// Base is the exported name the generated identifiers are derived
+	// from, such as Header.
+	Base string
+	// Option is the name of the generated option, such as WithHeader.
+	Option string
+	// Func is the name of the option from the func item of the option tag,
+	// such as OptLegacyTimeout, kept as is by the name block, Prefix and
+	// Style.
+	Func string
+	// Param is the parameter name of the generated option.
+	Param string
+	// Required marks options the generated constructor insists on.
+	Required bool
+	// OneOf is the group from the oneof item of the option tag, such as
+	// auth for oneof=auth. The generated constructor accepts the option of
+	// at most one field of a group, see OneOfs.
+	OneOf string
+	// Sensitive marks fields masked by the String and LogValue methods
+	// generated by GenerateRedacted.
+	Sensitive bool
+	// Short is the one letter shorthand of the flag of the field, from the
+	// short item of the option tag, such as u for -u and --base-url. It is
+	// only defined on the pflag.FlagSet of PFlag.
+	Short string
+	// Size reports whether the integer field holds a number of bytes, from
+	// the size item of the option tag. Its option gets a string variant
+	// like duration fields, see StringOption.
+	Size bool
+	// Checks validate the value passed to the generated option.
+	Checks []Check
+	// Enum are the values allowed by the enum item of the option tag, such
+	// as json, xml and proto for enum=json|xml|proto.
+	Enum []string
+	// EnumType is the string type generated for an enum field, with a
+	// constant per value, such as Format with FormatJSON.
+	EnumType string
+	// Deprecated is the deprecation message from the deprecated item of the
+	// option tag, such as "use WithHeaders". Deprecated options are
+	// generated by GenerateDeprecated.
+	Deprecated string
+	// Default is the Go expression of the default value from the default
+	// tag, empty when there is none.
+	Default string
+	// DefaultFunc is the function called for the default value, from a
+	// default tag such as `default:"func=newDefaultHTTPClient"`.
+	DefaultFunc string
+	// Elem is the element type of a slice or map field, set when Item is.
+	Elem string
+	// KeyType is the key type of a map field.
+	KeyType string
+	// Item is the name of the option adding a single element to a slice or
+	// map field, such as WithHeader for WithHeaders. It is generated for the
+	// fields of the struct itself.
+	Item string
+	// ItemParam is the parameter name of the element passed to Item. Map
+	// entries are passed as key and value.
+	ItemParam string
+	// Struct is the name of the field type when it is a struct, or a
+	// pointer to one, declared in the same package. For delegates it is the
+	// embedded type.
+	Struct string
+	// Pointer reports whether the field holds a pointer to Struct.
+	Pointer bool
+	// Embedded reports whether the field is embedded. The options of the
+	// fields of an embedded struct are named without its name, such as
+	// WithTimeout for the timeout field of an embedded BaseConfig.
+	Embedded bool
+	// Nested are the fields of Struct.
+	Nested []Field
+	// Setter is the existing SetX method of the struct for the field, nil
+	// if there is none.
+	Setter *Setter
+	// SetMethod reports whether a handwritten method called Set followed by
+	// Base exists, whatever its signature, so ModeSetters leaves it alone.
+	SetMethod bool
+	// Packages are the names of the packages referenced by Type.
+	Packages []string
+}
+
+// Type returns the struct type as referred to by the generated code,
+// instantiated with the type parameters of a generic struct.
+func (s *Struct) Type() string {
+	return s.Name + s.TypeArgs
+}
+
+// Required returns the fields whose option must be supplied.
+func (s *Struct) Required() []Field {
+	var required []Field
+	for _, f := range s.Fields {
+		if f.Required {
+			required = append(required, f)
+		}
+	}
+	return required
+}
+
+// OneOf is a group of fields of which at most one may be set.
+type OneOf struct {
+	// Name is the name of the group from the oneof item.
+	Name string
+	// Fields are the fields of the group in declaration order.
+	Fields []Field
+}
+
+// OneOfs returns the groups of the oneof items of the fields with more than
+// one field, in the order they first appear.
+func (s *Struct) OneOfs() []OneOf {
+	var groups []OneOf
+	index := map[string]int{}
+	for _, f := range s.Fields {
+		if f.OneOf == "" {
+			continue
+		}
+		i, ok := index[f.OneOf]
+		if !ok {
+			i = len(groups)
+			index[f.OneOf] = i
+			groups = append(groups, OneOf{Name: f.OneOf})
+		}
+		groups[i].Fields = append(groups[i].Fields, f)
+	}
+	result := groups[:0]
+	for _, g := range groups {
+		if len(g.Fields) > 1 {
+			result = append(result, g)
+		}
+	}
+	return result
+}
+
+// FieldImports returns the packages referenced by the types of the fields,
+// leaving out those only referenced by nested structs declared in the same
+// file.
+func (s *Struct) FieldImports() []Import {
+	return s.fieldImports(s.Fields)
+}
+
+// GeneratedSetters returns the fields ModeSetters generates a SetX method
+// for, those without a handwritten one.
+func (s *Struct) GeneratedSetters() []Field {
+	var result []Field
+	for _, f := range s.Fields {
+		if !f.SetMethod {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// SetterImports returns the packages referenced by the types of the fields
+// of GeneratedSetters.
+func (s *Struct) SetterImports() []Import {
+	return s.fieldImports(s.GeneratedSetters())
+}
+
+// fieldImports returns the packages referenced by the types of fields.
+func (s *Struct) fieldImports(fields []Field) []Import {
+	var imports []Import
+	for _, f := range fields {
+		for _, name := range f.Packages {
+			if i := slices.IndexFunc(s.Imports, func(i Import) bool { return i.Name == name }); i >= 0 {
+				imports = addImport(imports, s.Imports[i])
+			}
+		}
+	}
+	slices.SortFunc(imports, func(a, b Import) int { return strings.Compare(a.Path, b.Path) })
+	return imports
+}
+
+// DeclImports returns the packages needed by code declaring functions with
+// the type parameters of the struct and parameters of the field types.
+func (s *Struct) DeclImports() []Import {
+	imports := s.FieldImports()
+	for _, i := range s.ConstraintImports {
+		imports = addImport(imports, i)
+	}
+	slices.SortFunc(imports, func(a, b Import) int { return strings.Compare(a.Path, b.Path) })
+	return imports
+}
+
+// addImport appends i to imports unless its path is already imported.
+func addImport(imports []Import, i Import) []Import {
+	if slices.ContainsFunc(imports, func(o Import) bool { return o.Path == i.Path }) {
+		return imports
+	}
+	return append(imports, i)
+}
+
+// OptionImports returns the packages needed by the options file: those of
+// the type parameters, and those referenced by the types and checks of the
+// options it declares and the defaults it sets.
+func (s *Struct) OptionImports() []Import {
+	var fields []Field
+	for _, f := range s.Fields {
+		if f.Deprecated == "" {
+			fields = append(fields, f)
+		}
+	}
+	for _, o := range s.NestedOptions() {
+		fields = append(fields, o.Field)
+	}
+	fields = append(fields, s.Delegates...)
+	imports := s.importsFor(append(fields, s.Defaults()...))
+	for _, f := range s.Fields {
+		switch {
+		case f.Item == "" || f.Deprecated != "":
+		case f.KeyType != "":
+			imports = addImport(imports, Import{Name: "maps", Path: "maps"})
+		default:
+			imports = addImport(imports, Import{Name: "slices", Path: "slices"})
+		}
+	}
+	slices.SortFunc(imports, func(a, b Import) int { return strings.Compare(a.Path, b.Path) })
+	return imports
+}
+
+// importsFor returns the packages needed by options for fields.
+func (s *Struct) importsFor(fields []Field) []Import {
+	imports := s.typeImports(fields)
+	for _, f := range fields {
+		for _, c := range f.Checks {
+			for _, path := range c.Imports {
+				imports = addImport(imports, Import{Name: guessPackageName(path), Path: path})
+			}
+		}
+	}
+	slices.SortFunc(imports, func(a, b Import) int { return strings.Compare(a.Path, b.Path) })
+	return imports
+}
+
+// typeImports returns the packages referenced by the type parameters and
+// the types of fields.
+func (s *Struct) typeImports(fields []Field) []Import {
+	known := slices.Concat(s.Imports, s.NestedImports)
+	imports := slices.Clone(s.ConstraintImports)
+	for _, f := range fields {
+		for _, name := range f.Packages {
+			if i := slices.IndexFunc(known, func(i Import) bool { return i.Name == name }); i >= 0 {
+				imports = addImport(imports, known[i])
+			}
+		}
+	}
+	slices.SortFunc(imports, func(a, b Import) int { return strings.Compare(a.Path, b.Path) })
+	return imports
+}
+
+// NestedOption is an option for a field of a nested struct. It reaches the
+// nested struct through Getters, which are wired up with options.SubE.
+type NestedOption struct {
+	Field
+	// Owner is the name of the struct declaring Field.
+	Owner string
+	// Receiver is the variable name the generated code uses for Owner.
+	Receiver string
+	// Getters lead from the struct to Owner.
+	Getters []Getter
+}
+
+// Getter returns a nested struct from the struct holding it, allocating it
+// first when the field is a nil pointer.
+type Getter struct {
+	// From is the name of the struct holding the field.
+	From string
+	// To is the name of the nested struct.
+	To string
+	// Receiver is the variable name the generated code uses for From.
+	Receiver string
+	// Field is the name of the field holding To.
+	Field string
+	// Base is the exported name derived from Field.
+	Base string
+	// Pointer reports whether the field holds a pointer to To.
+	Pointer bool
+}
+
+// NestedOptions returns the options for the fields of nested structs, such
+// as WithRetryMaxAttempts for the maxAttempts field of a retry field.
+func (s *Struct) NestedOptions() []NestedOption {
+	var result []NestedOption
+	var walk func(owner, receiver string, fields []Field, getters []Getter)
+	walk = func(owner, receiver string, fields []Field, getters []Getter) {
+		for _, f := range fields {
+			if len(getters) > 0 {
+				result = append(result, NestedOption{Field: f, Owner: owner, Receiver: receiver, Getters: getters})
+			}
+			if f.Struct != "" {
+				g := Getter{From: owner, To: f.Struct, Receiver: receiver, Field: f.Name, Base: f.Base, Pointer: f.Pointer}
+				walk(f.Struct, receiverName(f.Struct), f.Nested, append(slices.Clone(getters), g))
+			}
+		}
+	}
+	walk(s.Type(), s.Receiver, s.Fields, nil)
+	return result
+}
+
+// DeprecatedFields returns the fields whose option is deprecated.
+func (s *Struct) DeprecatedFields() []Field {
+	var deprecated []Field
+	for _, f := range s.Fields {
+		if f.Deprecated != "" {
+			deprecated = append(deprecated, f)
+		}
+	}
+	return deprecated
+}
+
+// DeprecatedImports returns the packages needed by the deprecated options.
+func (s *Struct) DeprecatedImports() []Import {
+	return s.importsFor(s.DeprecatedFields())
+}
+
+// Constructor is a generated constructor taking positional parameters.
+type Constructor struct {
+	Name   string
+	Fields []Field
+}
+
+// Telescoping returns a constructor for every prefix of the fields, named
+// like NewWithBaseURL, NewWithBaseURLAndHeaders and
+// NewWithBaseURLHeadersAndLogger.
+func (s *Struct) Telescoping() []Constructor {
+	var ctors []Constructor
+	for n := 1; n <= len(s.Fields); n++ {
+		fields := s.Fields[:n]
+		name := "NewWith"
+		for i, f := range fields {
+			if i > 0 && i == n-1 {
+				name += "And"
+			}
+			name += f.Base
+		}
+		ctors = append(ctors, Constructor{Name: name, Fields: fields})
+	}
+	return ctors
+}
+
+// Import is a package imported by the generated code.
+type Import struct {
+	// Name is the name the package is referred to by.
+	Name string
+	// Path is the import path.
+	Path string
+}
+
+// Alias returns the name to put in front of the import path, which is empty
+// when the package name matches the last element of the path.
+func (i Import) Alias() string {
+	if i.Name == guessPackageName(i.Path) {
+		return ""
+	}
+	return i.Name
+}
+
+// Exclude removes the field called name from the fields options are
+// generated for, like tagging it `option:"-"`.
+func (s *Struct) Exclude(name string) error {
+	i := slices.IndexFunc(s.Fields, func(f Field) bool { return f.Name == name })
+	if i < 0 {
+		return fmt.Errorf("field %s not found in %s", name, s.Name)
+	}
+	s.Fields = slices.Delete(s.Fields, i, i+1)
+	return nil
+}
+
+// Rename derives the options of the field called name from base, like
+// tagging it `option:"name=<base>"`. A derived name of the option adding a
+// single element follows.
+func (s *Struct) Rename(name, base string) error {
+	i := slices.IndexFunc(s.Fields, func(f Field) bool { return f.Name == name })
+	if i < 0 {
+		return fmt.Errorf("field %s not found in %s", name, s.Name)
+	}
+	f := &s.Fields[i]
+	derived := f.Item == "With"+singular(*f)
+	f.Base, f.Option = base, "With"+base
+	if derived {
+		f.Item = "With" + singular(*f)
+	}
+	return nil
+}
+
+// Unexported selects how the options of unexported fields are generated.
+type Unexported string
+
+const (
+	// UnexportedPackage keeps the options in the package declaring the
+	// struct, where they assign the fields directly. It is the default
+	// unless Pkg is set.
+	UnexportedPackage Unexported = "package"
+	// UnexportedAccessors generates the exported OptionFields methods of
+	// GenerateAccessors, through which the options generated into Pkg reach
+	// the fields. It is the default when Pkg is set.
+	UnexportedAccessors Unexported = "accessors"
+	// UnexportedSkip generates no options for unexported fields, see
+	// SkipUnexported.
+	UnexportedSkip Unexported = "skip"
+)
+
+// UnexportedPolicies lists the supported policies for unexported fields.
+var UnexportedPolicies = []Unexported{UnexportedPackage, UnexportedAccessors, UnexportedSkip}
+
+// SkipUnexported removes the unexported fields, including those of nested
+// structs and the delegates, from the fields options are generated for and
+// returns their paths, such as Retry.maxAttempts.
+func (s *Struct) SkipUnexported() []string {
+	var skipped []string
+	var skip func(fields []Field, prefix string) []Field
+	skip = func(fields []Field, prefix string) []Field {
+		var kept []Field
+		for _, f := range fields {
+			if !token.IsExported(f.Name) {
+				skipped = append(skipped, prefix+f.Name)
+				continue
+			}
+			f.Nested = skip(f.Nested, prefix+f.Name+".")
+			kept = append(kept, f)
+		}
+		return kept
+	}
+	s.Fields = skip(s.Fields, "")
+	s.Delegates = skip(s.Delegates, "")
+	return skipped
+}
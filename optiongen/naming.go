@@ -0,0 +1,54 @@
+package optiongen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+// Style is the casing of the names of generated options.
+type Style string
+
+const (
+	// StyleCamel upper cases the first letter of the field name, such as
+	// WithHttpTimeout for httpTimeout. It is the default.
+	StyleCamel Style = "camel"
+	// StyleGo writes initialisms in upper case like Go identifiers, such as
+	// WithHTTPTimeout for httpTimeout.
+	StyleGo Style = "go"
+)
+
+// Styles lists the supported styles.
+var Styles = []Style{StyleCamel, StyleGo}
+
+// OptionPrefix returns the prefix of the generated options, With unless
+// Prefix is set.
+func (s *Struct) OptionPrefix() string {
+	if s.Prefix == "" {
+		return "With"
+	}
+	return s.Prefix
+}
+
+// styled returns name, derived from the names of fields, in the casing of
+// the Style of s.
+func (s *Struct) styled(name string) (string, error) {
+	switch s.Style {
+	case "", StyleCamel:
+		return name, nil
+	case StyleGo:
+		return camel(fields.SnakeCase(name)), nil
+	}
+	return "", fmt.Errorf("unknown style %q", s.Style)
+}
+
+// optionName returns the name of a generated option named "With" + base
+// by Parse, following the Prefix and Style of s.
+func (s *Struct) optionName(option string) (string, error) {
+	base, err := s.styled(strings.TrimPrefix(option, "With"))
+	if err != nil {
+		return "", err
+	}
+	return s.OptionPrefix() + base, nil
+}
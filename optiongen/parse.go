@@ -0,0 +1,591 @@
+package optiongen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+// Parse reads the Go package in dir and returns the model of the struct
+// called typeName.
+//
+// The `option` tag of a field controls its option: `option:"-"` excludes the
+// field, `option:"name=Headers"` renames the option to WithHeaders and
+// `option:"required"` makes the generated constructor fail when the option
+// is not supplied. Items are combined with commas. A `validate` tag adds
+// checks to the generated option, see Check, and a `default` tag a default
+// value applied by the generated constructor. `option:"deprecated=use
+// WithHeaders"` moves the option to the output of GenerateDeprecated, and
+// `option:"short=u"` gives the flag of the field a shorthand, see PFlag.
+// Fields sharing a group such as `option:"oneof=auth"` are mutually
+// exclusive, see OneOfs.
+func Parse(dir, typeName string) (*Struct, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parseDir(fset, dir, notTest, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range pkgs {
+		file, spec, st := lookupStruct(pkg.Files, typeName)
+		if st == nil {
+			continue
+		}
+		return newStruct(fset, pkg.Files, file, spec, st)
+	}
+	return nil, fmt.Errorf("struct %s not found in %s", typeName, dir)
+}
+
+// goPackage is a package parsed by parseDir. Paths holds the path of each
+// of Files.
+type goPackage struct {
+	Name  string
+	Paths []string
+	Files []*ast.File
+}
+
+// parseDir parses the .go files in dir whose names are accepted by keep,
+// all of them when keep is nil, and groups them by package. Files are
+// visited in the order of their names, so the output does not depend on
+// the file system, and packages in the order of their first file.
+func parseDir(fset *token.FileSet, dir string, keep func(name string) bool, mode parser.Mode) ([]*goPackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []*goPackage
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || keep != nil && !keep(e.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		file, err := parser.ParseFile(fset, path, nil, mode)
+		if err != nil {
+			return nil, err
+		}
+		i := slices.IndexFunc(pkgs, func(p *goPackage) bool { return p.Name == file.Name.Name })
+		if i < 0 {
+			pkgs = append(pkgs, &goPackage{Name: file.Name.Name})
+			i = len(pkgs) - 1
+		}
+		pkgs[i].Paths = append(pkgs[i].Paths, path)
+		pkgs[i].Files = append(pkgs[i].Files, file)
+	}
+	return pkgs, nil
+}
+
+// notTest accepts the names of files other than tests.
+func notTest(name string) bool {
+	return !strings.HasSuffix(name, "_test.go")
+}
+
+// lookupStruct finds the declaration of the struct typeName in files.
+func lookupStruct(files []*ast.File, typeName string) (*ast.File, *ast.TypeSpec, *ast.StructType) {
+	for _, file := range files {
+		if spec, st := findStruct(file, typeName); st != nil {
+			return file, spec, st
+		}
+	}
+	return nil, nil, nil
+}
+
+func findStruct(file *ast.File, typeName string) (*ast.TypeSpec, *ast.StructType) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != typeName {
+				continue
+			}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				return ts, st
+			}
+		}
+	}
+	return nil, nil
+}
+
+func newStruct(fset *token.FileSet, files []*ast.File, file *ast.File, spec *ast.TypeSpec, st *ast.StructType) (*Struct, error) {
+	s := &Struct{
+		Package:     file.Name.Name,
+		Name:        spec.Name.Name,
+		Receiver:    receiverName(spec.Name.Name),
+		Constructor: "new" + upperFirst(spec.Name.Name),
+	}
+	if spec.TypeParams != nil {
+		var params, args []string
+		used := map[string]bool{}
+		for _, field := range spec.TypeParams.List {
+			constraint, err := exprString(fset, field.Type)
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+			params = append(params, strings.Join(names, ", ")+" "+constraint)
+			args = append(args, names...)
+			collectPackages(field.Type, used)
+		}
+		s.TypeParams = "[" + strings.Join(params, ", ") + "]"
+		s.TypeArgs = "[" + strings.Join(args, ", ") + "]"
+		imports, err := resolveImports(file, used)
+		if err != nil {
+			return nil, err
+		}
+		s.ConstraintImports = imports
+	}
+
+	p := &fieldParser{fset: fset, files: files, imports: map[*ast.File]map[string]bool{}}
+	var err error
+	if s.Fields, err = p.parse(file, st, s.Receiver, "", map[string]bool{s.Name: true}); err != nil {
+		return nil, err
+	}
+	s.Delegates = p.delegates
+	if err := findSetters(fset, files, s.Name, s.Fields); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		used, ok := p.imports[f]
+		if !ok {
+			continue
+		}
+		imports, err := resolveImports(f, used)
+		if err != nil {
+			return nil, err
+		}
+		if f == file {
+			s.Imports = imports
+		} else {
+			s.NestedImports = append(s.NestedImports, imports...)
+		}
+	}
+	return s, nil
+}
+
+// fieldParser collects the fields of a struct and of the nested structs
+// declared in the same package.
+type fieldParser struct {
+	fset  *token.FileSet
+	files []*ast.File
+	// imports holds the package names referenced by the field types per
+	// declaring file.
+	imports map[*ast.File]map[string]bool
+	// delegates are the embedded fields with the delegate item.
+	delegates []Field
+}
+
+// parse returns the fields of st, declared in file. prefix is put in front
+// of the option names of nested structs, and chain holds the structs being
+// parsed to stop at recursive types.
+func (p *fieldParser) parse(file *ast.File, st *ast.StructType, receiver, prefix string, chain map[string]bool) ([]Field, error) {
+	var result []Field
+	for _, f := range st.Fields.List {
+		raw, err := structTag(f.Tag)
+		if err != nil {
+			return nil, err
+		}
+		tag := fields.ParseTag(raw.Get(fields.TagName))
+		if tag.Skip {
+			continue
+		}
+		if len(f.Names) == 0 {
+			field, err := p.embed(file, f, tag, receiver, prefix, chain)
+			if err != nil {
+				return nil, err
+			}
+			if field != nil {
+				result = append(result, *field)
+			}
+			continue
+		}
+		typ, err := exprString(p.fset, f.Type)
+		if err != nil {
+			return nil, err
+		}
+		if p.imports[file] == nil {
+			p.imports[file] = map[string]bool{}
+		}
+		packages := map[string]bool{}
+		collectPackages(f.Type, packages)
+		var names []string
+		for name := range packages {
+			p.imports[file][name] = true
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range f.Names {
+			if name.Name == "_" {
+				continue
+			}
+			base := upperFirst(name.Name)
+			if rename := tag.Items["name"]; rename != "" {
+				base = rename
+			}
+			key := tag.Key
+			if key == "" {
+				key = fields.SnakeCase(name.Name)
+			}
+			field := Field{
+				Name:       name.Name,
+				Type:       typ,
+				Key:        key,
+				Doc:        fieldDoc(f),
+				Base:       base,
+				Option:     "With" + prefix + base,
+				Func:       tag.Items["func"],
+				Param:      paramName(name.Name, receiver),
+				Required:   tag.Has("required"),
+				OneOf:      tag.Items["oneof"],
+				Sensitive:  tag.Has("sensitive"),
+				Short:      tag.Items["short"],
+				Deprecated: strings.TrimSuffix(tag.Items["deprecated"], "."),
+				Packages:   names,
+			}
+			if field.Func != "" {
+				field.Option = field.Func
+			}
+			if field.Short != "" && (len(field.Short) != 1 || !unicode.IsLetter(rune(field.Short[0])) && !unicode.IsDigit(rune(field.Short[0]))) {
+				return nil, fmt.Errorf("field %s: short %q is not a single letter or digit", name.Name, field.Short)
+			}
+			if field.Checks, err = parseChecks(field, raw.Get("validate")); err != nil {
+				return nil, fmt.Errorf("field %s: %w", name.Name, err)
+			}
+			if tag.Has("size") {
+				if err := size(&field); err != nil {
+					return nil, fmt.Errorf("field %s: %w", name.Name, err)
+				}
+			}
+			if tag.Has("enum") {
+				if err := enum(&field, tag.Items["enum"], prefix); err != nil {
+					return nil, fmt.Errorf("field %s: %w", name.Name, err)
+				}
+			}
+			if value, ok := raw.Lookup("default"); ok {
+				if field.Default, err = defaultValue(&field, value); err != nil {
+					return nil, fmt.Errorf("field %s: %w", name.Name, err)
+				}
+			}
+			if err := p.collection(&field, f.Type, tag, prefix, receiver); err != nil {
+				return nil, fmt.Errorf("field %s: %w", name.Name, err)
+			}
+			if err := p.nest(&field, f.Type, prefix, chain); err != nil {
+				return nil, err
+			}
+			result = append(result, field)
+		}
+	}
+	return result, nil
+}
+
+// embed handles the embedded field f. Its fields are promoted to options of
+// their own, named like those of the embedding struct, when f embeds a
+// struct declared in the same package. With the delegate item, f gets a
+// single option applying the options generated for the embedded type and
+// is recorded in p.delegates instead. Other embedded fields are not
+// configurable through options.
+func (p *fieldParser) embed(file *ast.File, f *ast.Field, tag fields.Tag, receiver, prefix string, chain map[string]bool) (*Field, error) {
+	typ, err := exprString(p.fset, f.Type)
+	if err != nil {
+		return nil, err
+	}
+	expr, pointer := f.Type, false
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr, pointer = star.X, true
+	}
+	var name string
+	switch x := expr.(type) {
+	case *ast.Ident:
+		name = x.Name
+	case *ast.SelectorExpr:
+		name = x.Sel.Name
+	default:
+		return nil, nil
+	}
+	field := Field{
+		Name:     name,
+		Type:     typ,
+		Key:      tag.Key,
+		Doc:      fieldDoc(f),
+		Base:     name,
+		Option:   "With" + prefix + name,
+		Param:    paramName(name, receiver),
+		Pointer:  pointer,
+		Embedded: true,
+	}
+	if field.Key == "" {
+		field.Key = fields.SnakeCase(name)
+	}
+
+	if tag.Has("delegate") {
+		if prefix != "" {
+			return nil, fmt.Errorf("embedded field %s: delegate is only supported in the struct options are generated for", name)
+		}
+		field.Struct = strings.TrimPrefix(typ, "*")
+		packages := map[string]bool{}
+		collectPackages(f.Type, packages)
+		if p.imports[file] == nil {
+			p.imports[file] = map[string]bool{}
+		}
+		for pkg := range packages {
+			p.imports[file][pkg] = true
+			field.Packages = append(field.Packages, pkg)
+		}
+		sort.Strings(field.Packages)
+		p.delegates = append(p.delegates, field)
+		return nil, nil
+	}
+
+	id, ok := expr.(*ast.Ident)
+	if !ok || chain[id.Name] {
+		return nil, nil
+	}
+	embedded, _, st := lookupStruct(p.files, id.Name)
+	if st == nil {
+		return nil, nil
+	}
+	chain[id.Name] = true
+	defer delete(chain, id.Name)
+	nested, err := p.parse(embedded, st, receiverName(id.Name), prefix, chain)
+	if err != nil {
+		return nil, fmt.Errorf("embedded field %s: %w", name, err)
+	}
+	field.Struct = id.Name
+	field.Nested = nested
+	return &field, nil
+}
+
+// collection names the option adding a single element to a slice or map
+// field, such as WithHeader for WithHeaders. The name is the singular of
+// the field name, or set by the item item of the option tag; item=-
+// disables the option.
+func (p *fieldParser) collection(field *Field, expr ast.Expr, tag fields.Tag, prefix, receiver string) error {
+	var err error
+	switch t := expr.(type) {
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return nil
+		}
+		field.Elem, err = exprString(p.fset, t.Elt)
+	case *ast.MapType:
+		if field.KeyType, err = exprString(p.fset, t.Key); err == nil {
+			field.Elem, err = exprString(p.fset, t.Value)
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	item := tag.Items["item"]
+	switch item {
+	case "-":
+		field.Elem, field.KeyType = "", ""
+		return nil
+	case "":
+		item = singular(*field)
+	}
+	field.Item = "With" + prefix + item
+	if field.Item == field.Option {
+		return fmt.Errorf("option %s for a single element clashes with the option for the field, set the item item of the option tag", field.Item)
+	}
+	switch field.ItemParam = paramName(item, receiver); {
+	case field.KeyType != "":
+		field.ItemParam = "value"
+	case field.ItemParam == field.Param:
+		field.ItemParam = "item"
+	}
+	return nil
+}
+
+// singular derives the name of the option adding a single element to the
+// collection held by f from its Base, such as Header for Headers.
+func singular(f Field) string {
+	switch {
+	case strings.HasSuffix(f.Base, "ies"):
+		return strings.TrimSuffix(f.Base, "ies") + "y"
+	case strings.HasSuffix(f.Base, "s") && !strings.HasSuffix(f.Base, "ss"):
+		return strings.TrimSuffix(f.Base, "s")
+	case f.KeyType != "":
+		return f.Base + "Entry"
+	}
+	return f.Base + "Item"
+}
+
+// nest fills the nested fields of field when its type is a struct, or a
+// pointer to one, declared in the same package.
+func (p *fieldParser) nest(field *Field, expr ast.Expr, prefix string, chain map[string]bool) error {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		field.Pointer = true
+		expr = star.X
+	}
+	id, ok := expr.(*ast.Ident)
+	if !ok || chain[id.Name] {
+		field.Pointer = false
+		return nil
+	}
+	file, _, st := lookupStruct(p.files, id.Name)
+	if st == nil {
+		field.Pointer = false
+		return nil
+	}
+
+	chain[id.Name] = true
+	defer delete(chain, id.Name)
+	nested, err := p.parse(file, st, receiverName(id.Name), prefix+field.Base, chain)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", field.Name, err)
+	}
+	field.Struct = id.Name
+	field.Nested = nested
+	return nil
+}
+
+// fieldDoc returns the doc comment of f, falling back to its line comment.
+func fieldDoc(f *ast.Field) string {
+	if f.Doc != nil {
+		return strings.TrimSpace(f.Doc.Text())
+	}
+	if f.Comment != nil {
+		return strings.TrimSpace(f.Comment.Text())
+	}
+	return ""
+}
+
+// structTag unquotes the tag of a field declaration.
+func structTag(lit *ast.BasicLit) (reflect.StructTag, error) {
+	if lit == nil {
+		return "", nil
+	}
+	raw, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", err
+	}
+	return reflect.StructTag(raw), nil
+}
+
+func exprString(fset *token.FileSet, expr ast.Expr) (string, error) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// collectPackages adds the package names referenced by expr to used.
+func collectPackages(expr ast.Expr, used map[string]bool) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+}
+
+// resolveImports maps the used package names to the imports of file.
+func resolveImports(file *ast.File, used map[string]bool) ([]Import, error) {
+	var imports []Import
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		name := guessPackageName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if used[name] {
+			imports = append(imports, Import{Name: name, Path: path})
+		}
+	}
+	sort.Slice(imports, func(i, j int) bool { return imports[i].Path < imports[j].Path })
+	return imports, nil
+}
+
+var versionSuffix = regexp.MustCompile(`^v[0-9]+$|\.v[0-9]+$`)
+
+// guessPackageName returns the conventional package name for an import
+// path, such as yaml for gopkg.in/yaml.v3.
+func guessPackageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if versionSuffix.MatchString(name) && len(parts) > 1 && !strings.Contains(name, ".") {
+		name = parts[len(parts)-2]
+	}
+	name = versionSuffix.ReplaceAllString(name, "")
+	name = strings.TrimPrefix(name, "go-")
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// receiverName returns the variable name used for a value of typeName.
+func receiverName(typeName string) string {
+	return strings.ToLower(typeName[:1])
+}
+
+func upperFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// lowerInitial lower cases the leading upper case letters of s, but the
+// last one when it starts the next word, such as url for URL and apiKey for
+// APIKey.
+func lowerInitial(s string) string {
+	r := []rune(s)
+	n := 0
+	for n < len(r) && unicode.IsUpper(r[n]) {
+		n++
+	}
+	if n > 1 && n < len(r) && unicode.IsLower(r[n]) {
+		n--
+	}
+	for i := range n {
+		r[i] = unicode.ToLower(r[i])
+	}
+	return string(r)
+}
+
+// reserved are the package names the generated code may refer to.
+// Predeclared identifiers such as error are avoided as well.
+var reserved = map[string]bool{"options": true, "fmt": true, "url": true, "mail": true, "maps": true, "slices": true}
+
+// paramName derives the parameter name of an option from the field name,
+// lower casing a leading initialism as a whole, such as httpClient for
+// HTTPClient, and avoiding keywords, the receiver name and the packages the
+// generated code uses.
+func paramName(field, receiver string) string {
+	name := lowerInitial(field)
+	if token.IsKeyword(name) || name == receiver || reserved[name] || types.Universe.Lookup(name) != nil {
+		name += "Value"
+	}
+	return name
+}
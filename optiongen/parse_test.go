@@ -0,0 +1,70 @@
+package optiongen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParamName(t *testing.T) {
+	tests := map[string]string{
+		"Host":       "host",
+		"baseURL":    "baseURL",
+		"HTTPClient": "httpClient",
+		"APIKey":     "apiKey",
+		"ID":         "id",
+		"TLS2":       "tls2",
+		"URL":        "urlValue",
+		"Type":       "typeValue",
+		"c":          "cValue",
+	}
+	for field, want := range tests {
+		if got := paramName(field, "c"); got != want {
+			t.Errorf("paramName(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.go":      "package a\n",
+		"a.go":      "package a\n",
+		"a_test.go": "package a_test\n",
+		"notes.txt": "not go",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub.go"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := func(keep func(string) bool) map[string][]string {
+		pkgs, err := parseDir(token.NewFileSet(), dir, keep, parser.PackageClauseOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string][]string{}
+		for _, pkg := range pkgs {
+			for _, path := range pkg.Paths {
+				got[pkg.Name] = append(got[pkg.Name], filepath.Base(path))
+			}
+		}
+		return got
+	}
+	if got, want := paths(notTest), map[string][]string{"a": {"a.go", "b.go"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDir(notTest) = %v, want %v", got, want)
+	}
+	if got, want := paths(nil), map[string][]string{"a": {"a.go", "b.go"}, "a_test": {"a_test.go"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDir(nil) = %v, want %v", got, want)
+	}
+	if _, err := parseDir(token.NewFileSet(), filepath.Join(dir, "missing"), nil, 0); err == nil {
+		t.Error("parseDir() succeeded for a missing directory")
+	}
+}
@@ -0,0 +1,188 @@
+package optiongen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// OutputPackage returns the name of the package the options are generated
+// into.
+func (s *Struct) OutputPackage() string {
+	if s.Pkg != "" {
+		return s.Pkg
+	}
+	return s.Package
+}
+
+// Ref returns typ as referred to by the generated options, qualified with
+// the package of the struct when they are generated into Pkg.
+func (s *Struct) Ref(typ string) (string, error) {
+	if s.Pkg == "" {
+		return typ, nil
+	}
+	return qualify(typ, s.Package, s.typeParamNames())
+}
+
+// Params returns TypeParams with the constraints qualified like Ref.
+func (s *Struct) Params() (string, error) {
+	if s.Pkg == "" || s.TypeParams == "" {
+		return s.TypeParams, nil
+	}
+	decl, err := typeParams(s.TypeParams)
+	if err != nil {
+		return "", err
+	}
+	var params []string
+	for _, field := range decl.List {
+		constraint, err := exprString(token.NewFileSet(), field.Type)
+		if err != nil {
+			return "", err
+		}
+		if constraint, err = s.Ref(constraint); err != nil {
+			return "", err
+		}
+		var names []string
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		params = append(params, strings.Join(names, ", ")+" "+constraint)
+	}
+	return "[" + strings.Join(params, ", ") + "]", nil
+}
+
+// Lvalue returns the expression assigning the field called name, whose
+// accessor is called base, of the struct held by receiver.
+func (s *Struct) Lvalue(receiver, name, base string) string {
+	if s.Pkg == "" {
+		return receiver + "." + name
+	}
+	return "*" + receiver + ".OptionFields()." + base
+}
+
+// Addr returns the expression taking the address of the field like Lvalue.
+func (s *Struct) Addr(receiver, name, base string) string {
+	if s.Pkg == "" {
+		return "&" + receiver + "." + name
+	}
+	return receiver + ".OptionFields()." + base
+}
+
+// PackageImport returns the import of the package declaring the struct
+// when the options are generated into Pkg.
+func (s *Struct) PackageImport() *Import {
+	if s.Pkg == "" {
+		return nil
+	}
+	return &Import{Name: s.Package, Path: s.ImportPath}
+}
+
+// Accessor is a struct whose fields are exposed to the options generated
+// into Pkg.
+type Accessor struct {
+	// Name is the name of the struct.
+	Name string
+	// TypeParams and TypeArgs are those of a generic struct.
+	TypeParams, TypeArgs string
+	// Receiver is the variable name the generated code uses for the struct.
+	Receiver string
+	// Fields are the fields that options are generated for.
+	Fields []Field
+}
+
+// Accessors returns the struct and the nested structs, which get an
+// OptionFields method when the options are generated into Pkg.
+func (s *Struct) Accessors() []Accessor {
+	result := []Accessor{{Name: s.Name, TypeParams: s.TypeParams, TypeArgs: s.TypeArgs, Receiver: s.Receiver, Fields: s.Fields}}
+	var walk func(fields []Field)
+	walk = func(fields []Field) {
+		for _, f := range fields {
+			if f.Struct == "" || slices.ContainsFunc(result, func(a Accessor) bool { return a.Name == f.Struct }) {
+				continue
+			}
+			result = append(result, Accessor{Name: f.Struct, Receiver: receiverName(f.Struct), Fields: f.Nested})
+			walk(f.Nested)
+		}
+	}
+	walk(s.Fields)
+	return result
+}
+
+// AccessorImports returns the packages needed by the accessors.
+func (s *Struct) AccessorImports() []Import {
+	imports := s.DeclImports()
+	if len(s.Accessors()) > 1 {
+		for _, i := range slices.Concat(s.Imports, s.NestedImports) {
+			imports = addImport(imports, i)
+		}
+	}
+	slices.SortFunc(imports, func(a, b Import) int { return strings.Compare(a.Path, b.Path) })
+	return imports
+}
+
+func (s *Struct) typeParamNames() []string {
+	return strings.FieldsFunc(strings.Trim(s.TypeArgs, "[]"), func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// typeParams parses a type parameter list such as [K comparable, V any].
+func typeParams(list string) (*ast.FieldList, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\nfunc _"+list+"() {}", 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing type parameters %s: %w", list, err)
+	}
+	return file.Decls[0].(*ast.FuncDecl).Type.TypeParams, nil
+}
+
+// qualify prefixes the identifiers of the types declared in package pkg,
+// found in the type expression typ, with the package name. Unexported types
+// cannot be referred to from another package and are reported as an error.
+func qualify(typ, pkg string, params []string) (string, error) {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return "", fmt.Errorf("parsing type %s: %w", typ, err)
+	}
+
+	skip := map[*ast.Ident]bool{}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			skip[n.Sel] = true
+			if id, ok := n.X.(*ast.Ident); ok {
+				skip[id] = true
+			}
+		case *ast.Field:
+			for _, name := range n.Names {
+				skip[name] = true
+			}
+		}
+		return true
+	})
+
+	var unexported error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || skip[id] || slices.Contains(params, id.Name) || types.Universe.Lookup(id.Name) != nil {
+			return true
+		}
+		if !ast.IsExported(id.Name) {
+			unexported = fmt.Errorf("type %s refers to unexported %s, which package %s does not export", typ, id.Name, pkg)
+		}
+		id.Name = pkg + "." + id.Name
+		return true
+	})
+	if unexported != nil {
+		return "", unexported
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), expr); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,159 @@
+package optiongen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+)
+
+// OutputPlugin generates further files from the model of a struct, such as
+// mocks or documentation, next to the output of the built-in modes.
+//
+// Plugins linked into a program are made available with Register. Others
+// run as separate executables, see ExecPlugin and ServePlugin, so the
+// command does not have to be forked to add an output.
+type OutputPlugin interface {
+	// Name identifies the plugin, such as mock.
+	Name() string
+	// Generate returns the files to write for s. The options of s are named
+	// as in the generated code.
+	Generate(s *Struct) ([]File, error)
+}
+
+// File is a file emitted by an OutputPlugin.
+type File struct {
+	// Name is the slash separated path of the file, relative to the
+	// directory of the package declaring the struct, such as
+	// client_mock_gen.go.
+	Name string `json:"name"`
+	// Content is the content of the file.
+	Content []byte `json:"content"`
+}
+
+var plugins = map[string]OutputPlugin{}
+
+// Register makes p available to LookupPlugin under its name. It panics if
+// the name is registered twice.
+func Register(p OutputPlugin) {
+	if _, ok := plugins[p.Name()]; ok {
+		panic("optiongen: plugin " + p.Name() + " registered twice")
+	}
+	plugins[p.Name()] = p
+}
+
+// Plugins returns the names of the registered plugins, sorted.
+func Plugins() []string {
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupPlugin returns the plugin registered as name, or an ExecPlugin
+// running optiongen-<name> from the PATH otherwise.
+func LookupPlugin(name string) OutputPlugin {
+	if p, ok := plugins[name]; ok {
+		return p
+	}
+	return ExecPlugin{Command: "optiongen-" + name}
+}
+
+// RunPlugin runs p for s. The options of s are named by the name block of
+// its Overrides first, and the names of the files are checked to stay in
+// the package directory.
+func RunPlugin(p OutputPlugin, s *Struct) ([]File, error) {
+	set, err := s.templateSet()
+	if err != nil {
+		return nil, err
+	}
+	if s, err = s.renamed(set); err != nil {
+		return nil, err
+	}
+	files, err := p.Generate(s)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.Name(), err)
+	}
+	for _, f := range files {
+		if f.Name == "" || path.IsAbs(f.Name) || path.Clean(f.Name) != f.Name || strings.HasPrefix(f.Name, "../") || f.Name == ".." {
+			return nil, fmt.Errorf("plugin %s: invalid file name %q", p.Name(), f.Name)
+		}
+	}
+	return files, nil
+}
+
+// ExecPlugin is an OutputPlugin running an executable. The executable reads
+// the Struct as JSON from its standard input and writes the files as a
+// JSON array to its standard output, which ServePlugin implements.
+type ExecPlugin struct {
+	// Command is the name or path of the executable.
+	Command string
+	// Args are passed to the executable.
+	Args []string
+}
+
+// Name returns the base name of Command without the optiongen- prefix.
+func (p ExecPlugin) Name() string {
+	return strings.TrimPrefix(path.Base(strings.ReplaceAll(p.Command, "\\", "/")), "optiongen-")
+}
+
+// Generate runs the executable for s.
+func (p ExecPlugin) Generate(s *Struct) ([]File, error) {
+	input, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(p.Command, p.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	var files []File
+	if err := json.Unmarshal(stdout.Bytes(), &files); err != nil {
+		return nil, fmt.Errorf("decoding output of %s: %w", p.Command, err)
+	}
+	return files, nil
+}
+
+// ServePlugin implements the executable side of ExecPlugin for p, to be
+// called from the main function of an optiongen-<name> command:
+//
+//	func main() {
+//		optiongen.ServePlugin(mockPlugin{})
+//	}
+//
+// Errors are printed to the standard error and end the process.
+func ServePlugin(p OutputPlugin) {
+	if err := servePlugin(p, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "optiongen-%s: %v\n", p.Name(), err)
+		os.Exit(1)
+	}
+}
+
+func servePlugin(p OutputPlugin, r io.Reader, w io.Writer) error {
+	var s Struct
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("decoding struct: %w", err)
+	}
+	files, err := p.Generate(&s)
+	if err != nil {
+		return err
+	}
+	if files == nil {
+		files = []File{}
+	}
+	return json.NewEncoder(w).Encode(files)
+}
@@ -0,0 +1,143 @@
+package optiongen
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// listPlugin emits a file listing the options of a struct, or the file
+// names it is told to.
+type listPlugin struct {
+	names []string
+	err   error
+}
+
+func (listPlugin) Name() string { return "list" }
+
+func (p listPlugin) Generate(s *Struct) ([]File, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	var options []string
+	for _, f := range s.Fields {
+		options = append(options, f.Option)
+	}
+	content := []byte(strings.Join(options, "\n"))
+	if p.names == nil {
+		return []File{{Name: strings.ToLower(s.Name) + "_list.txt", Content: content}}, nil
+	}
+	var files []File
+	for _, name := range p.names {
+		files = append(files, File{Name: name, Content: content})
+	}
+	return files, nil
+}
+
+func TestRunPlugin(t *testing.T) {
+	s, err := Parse(filepath.Join("testdata", "bridge"), "Store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := RunPlugin(listPlugin{}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []File{{Name: "store_list.txt", Content: []byte("WithName\nWithLabels\nWithLimit\nWithTags")}}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("RunPlugin() = %q, want %q", files, want)
+	}
+
+	for _, name := range []string{"", "/abs.go", "../up.go", "..", "a/../b.go", "./a.go"} {
+		if _, err := RunPlugin(listPlugin{names: []string{name}}, s); err == nil {
+			t.Errorf("RunPlugin() accepted the file name %q", name)
+		}
+	}
+	if _, err := RunPlugin(listPlugin{names: []string{"mocks/store.go"}}, s); err != nil {
+		t.Errorf("RunPlugin() rejected a file in a sub-directory: %v", err)
+	}
+	if _, err := RunPlugin(listPlugin{err: errors.New("broken")}, s); err == nil || err.Error() != "plugin list: broken" {
+		t.Errorf("RunPlugin() error = %v, want plugin list: broken", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(listPlugin{})
+	defer delete(plugins, "list")
+	if got := Plugins(); !reflect.DeepEqual(got, []string{"list"}) {
+		t.Errorf("Plugins() = %q, want list", got)
+	}
+	if _, ok := LookupPlugin("list").(listPlugin); !ok {
+		t.Error("LookupPlugin(list) did not return the registered plugin")
+	}
+	if got, want := LookupPlugin("mock"), (ExecPlugin{Command: "optiongen-mock"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("LookupPlugin(mock) = %+v, want %+v", got, want)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() did not panic for a duplicate name")
+		}
+	}()
+	Register(listPlugin{})
+}
+
+func TestServePlugin(t *testing.T) {
+	var out bytes.Buffer
+	err := servePlugin(listPlugin{}, strings.NewReader(`{"Name": "Store", "Fields": [{"Option": "WithName"}]}`), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), `[{"name":"store_list.txt","content":"V2l0aE5hbWU="}]`+"\n"; got != want {
+		t.Errorf("servePlugin() wrote %s, want %s", got, want)
+	}
+	if err := servePlugin(listPlugin{}, strings.NewReader("{"), &out); err == nil {
+		t.Error("servePlugin() succeeded on malformed input")
+	}
+}
+
+func TestExecPluginName(t *testing.T) {
+	for command, want := range map[string]string{
+		"optiongen-mock":             "mock",
+		"/usr/bin/optiongen-docs":    "docs",
+		`C:\tools\optiongen-sql.exe`: "sql.exe",
+		"custom":                     "custom",
+	} {
+		if got := (ExecPlugin{Command: command}).Name(); got != want {
+			t.Errorf("ExecPlugin{%q}.Name() = %q, want %q", command, got, want)
+		}
+	}
+}
+
+func TestExecPlugin(t *testing.T) {
+	t.Setenv("OPTIONGEN_HELPER_PLUGIN", "1")
+	p := ExecPlugin{Command: os.Args[0], Args: []string{"-test.run=^TestHelperPlugin$"}}
+	files, err := p.Generate(&Struct{Name: "Store", Fields: []Field{{Option: "WithName"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []File{{Name: "store_list.txt", Content: []byte("WithName")}}; !reflect.DeepEqual(files, want) {
+		t.Errorf("Generate() = %q, want %q", files, want)
+	}
+
+	t.Setenv("OPTIONGEN_HELPER_PLUGIN", "fail")
+	if _, err := p.Generate(&Struct{Name: "Store"}); err == nil || !strings.Contains(err.Error(), "optiongen-list: broken") {
+		t.Errorf("Generate() error = %v, want the standard error of the plugin", err)
+	}
+}
+
+// TestHelperPlugin is the executable run by TestExecPlugin.
+func TestHelperPlugin(t *testing.T) {
+	switch os.Getenv("OPTIONGEN_HELPER_PLUGIN") {
+	case "1":
+		ServePlugin(listPlugin{})
+	case "fail":
+		ServePlugin(listPlugin{err: errors.New("broken")})
+	default:
+		return
+	}
+	os.Exit(0)
+}
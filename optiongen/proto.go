@@ -0,0 +1,620 @@
+package optiongen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+// ProtoFile is the parsed model of a .proto file, reduced to what Go
+// structs are generated from: messages, their fields and enums. Services,
+// options other than go_package and extensions are skipped.
+type ProtoFile struct {
+	// Name is the base name of the file, such as config.proto.
+	Name string
+	// Package is the proto package, such as acme.config.v1.
+	Package string
+	// GoPackage is the go_package option, such as
+	// github.com/acme/config;configpb.
+	GoPackage string
+	// Messages are the messages of the file, nested messages following
+	// the message declaring them.
+	Messages []*ProtoMessage
+	// Enums are the enums of the file, including nested ones.
+	Enums []*ProtoEnum
+}
+
+// ProtoMessage is a message of a ProtoFile.
+type ProtoMessage struct {
+	// Name is the name of the message, such as Retry.
+	Name string
+	// FullName is the name qualified by the declaring messages, such as
+	// Server.Retry.
+	FullName string
+	// Doc is the leading comment of the message.
+	Doc string
+	// Fields are the fields of the message, including those of oneofs.
+	Fields []ProtoField
+	// Nested reports whether the message is declared in another message.
+	Nested bool
+}
+
+// ProtoField is a field of a ProtoMessage.
+type ProtoField struct {
+	// Name is the name of the field, such as base_url.
+	Name string
+	// Type is the type as written, such as string or Retry. For maps it is
+	// the value type.
+	Type string
+	// KeyType is the key type of a map field.
+	KeyType string
+	// Repeated reports whether the field is repeated.
+	Repeated bool
+	// Doc is the leading comment of the field.
+	Doc string
+	// Deprecated reports whether the field has the deprecated option.
+	Deprecated bool
+	// OneOf is the name of the oneof declaring the field, empty for other
+	// fields.
+	OneOf string
+}
+
+// ProtoEnum is an enum of a ProtoFile.
+type ProtoEnum struct {
+	// FullName is the name qualified by the declaring messages.
+	FullName string
+	// Values are the names of the values, such as FORMAT_JSON.
+	Values []string
+}
+
+// ParseProto reads the .proto file at path.
+func ParseProto(path string) (*ProtoFile, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{tokens: protoTokens(string(src))}
+	f := &ProtoFile{Name: filepath.Base(path)}
+	if err := p.file(f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// protoToken is a token of a .proto file with the comment directly in
+// front of it.
+type protoToken struct {
+	text string
+	doc  string
+	line int
+}
+
+// protoTokens splits src into identifiers, numbers, quoted strings and
+// punctuation, attaching comments to the token that follows them.
+func protoTokens(src string) []protoToken {
+	var tokens []protoToken
+	var doc []string
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			doc = append(doc, strings.TrimSpace(src[i+2:i+end]))
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			text := src[i+2 : i+2+end]
+			line += strings.Count(text, "\n")
+			for _, l := range strings.Split(text, "\n") {
+				doc = append(doc, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), "*")))
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(src))
+			tokens = append(tokens, protoToken{text: src[i:end], doc: strings.TrimSpace(strings.Join(doc, "\n")), line: line})
+			doc = nil
+			i = end
+		case isProtoIdent(rune(c)):
+			end := i
+			for end < len(src) && (isProtoIdent(rune(src[end])) || src[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, protoToken{text: src[i:end], doc: strings.TrimSpace(strings.Join(doc, "\n")), line: line})
+			doc = nil
+			i = end
+		default:
+			tokens = append(tokens, protoToken{text: string(c), doc: strings.TrimSpace(strings.Join(doc, "\n")), line: line})
+			doc = nil
+			i++
+		}
+	}
+	return tokens
+}
+
+func isProtoIdent(r rune) bool {
+	return r == '_' || r == '-' || r == '+' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// protoParser parses the tokens of a .proto file.
+type protoParser struct {
+	tokens []protoToken
+	pos    int
+}
+
+func (p *protoParser) peek() protoToken {
+	if p.pos >= len(p.tokens) {
+		return protoToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *protoParser) next() protoToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *protoParser) expect(text string) error {
+	if t := p.next(); t.text != text {
+		return p.errorf(t, "expected %q, got %q", text, t.text)
+	}
+	return nil
+}
+
+func (p *protoParser) errorf(t protoToken, format string, args ...any) error {
+	if t.text == "" {
+		return fmt.Errorf("unexpected end of file: "+format, args...)
+	}
+	return fmt.Errorf("line %d: "+format, append([]any{t.line}, args...)...)
+}
+
+// skip skips a statement up to its semicolon or a block up to its closing
+// brace.
+func (p *protoParser) skip() error {
+	depth := 0
+	for {
+		t := p.next()
+		switch t.text {
+		case "":
+			return p.errorf(t, "unterminated statement")
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		case ";":
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+func (p *protoParser) file(f *ProtoFile) error {
+	for p.pos < len(p.tokens) {
+		t := p.next()
+		switch t.text {
+		case "package":
+			f.Package = p.next().text
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "option":
+			name := p.next().text
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			value := p.next().text
+			if name == "go_package" {
+				unquoted, err := strconv.Unquote(value)
+				if err != nil {
+					return p.errorf(t, "go_package: %v", err)
+				}
+				f.GoPackage = unquoted
+			}
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "message":
+			if err := p.message(f, t, ""); err != nil {
+				return err
+			}
+		case "enum":
+			if err := p.enum(f, ""); err != nil {
+				return err
+			}
+		case ";":
+		default:
+			// syntax, edition, import, service and extend.
+			p.pos--
+			if err := p.skip(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *protoParser) message(f *ProtoFile, start protoToken, scope string) error {
+	m := &ProtoMessage{Name: p.next().text, Doc: start.doc, Nested: scope != ""}
+	m.FullName = scope + m.Name
+	f.Messages = append(f.Messages, m)
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	return p.body(f, m, "}", "")
+}
+
+// body parses the fields, nested messages and enums of m up to end. oneof
+// is the name of the oneof the fields belong to, if any.
+func (p *protoParser) body(f *ProtoFile, m *ProtoMessage, end, oneof string) error {
+	for {
+		t := p.next()
+		switch t.text {
+		case end:
+			return nil
+		case "":
+			return p.errorf(t, "unterminated message %s", m.Name)
+		case ";":
+		case "message":
+			if err := p.message(f, t, m.FullName+"."); err != nil {
+				return err
+			}
+		case "enum":
+			if err := p.enum(f, m.FullName+"."); err != nil {
+				return err
+			}
+		case "oneof":
+			name := p.next().text
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.body(f, m, "}", name); err != nil {
+				return err
+			}
+		case "option", "reserved", "extensions", "extend":
+			p.pos--
+			if err := p.skip(); err != nil {
+				return err
+			}
+		default:
+			field := ProtoField{Doc: t.doc, OneOf: oneof}
+			switch t.text {
+			case "repeated":
+				field.Repeated = true
+				t = p.next()
+			case "optional", "required":
+				t = p.next()
+			}
+			field.Type = t.text
+			if t.text == "map" {
+				if err := p.expect("<"); err != nil {
+					return err
+				}
+				field.KeyType = p.next().text
+				if err := p.expect(","); err != nil {
+					return err
+				}
+				field.Type = p.next().text
+				if err := p.expect(">"); err != nil {
+					return err
+				}
+			}
+			field.Name = p.next().text
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			p.next()
+			if p.peek().text == "[" {
+				for t := p.next(); t.text != "]"; t = p.next() {
+					if t.text == "" {
+						return p.errorf(t, "unterminated options of field %s", field.Name)
+					}
+					if t.text == "deprecated" && p.peek().text == "=" {
+						p.next()
+						field.Deprecated = p.peek().text == "true"
+					}
+				}
+			}
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+			m.Fields = append(m.Fields, field)
+		}
+	}
+}
+
+func (p *protoParser) enum(f *ProtoFile, scope string) error {
+	e := &ProtoEnum{FullName: scope + p.next().text}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		t := p.next()
+		switch t.text {
+		case "}":
+			f.Enums = append(f.Enums, e)
+			return nil
+		case "":
+			return p.errorf(t, "unterminated enum %s", e.FullName)
+		case ";":
+		case "option", "reserved":
+			p.pos--
+			if err := p.skip(); err != nil {
+				return err
+			}
+		default:
+			e.Values = append(e.Values, t.text)
+			// The number and the options of the value.
+			for p.peek().text != ";" && p.peek().text != "" {
+				p.next()
+			}
+		}
+	}
+}
+
+// ProtoStruct is a Go struct generated by GenerateProto for a message.
+type ProtoStruct struct {
+	// Name is the name of the struct, such as ServerRetry for the nested
+	// message Server.Retry.
+	Name string
+	// Receiver is the receiver name of the accessors of the fields.
+	Receiver string
+	// Message is the full name of the message.
+	Message string
+	// Doc is the doc comment of the struct.
+	Doc string
+	// Fields are the fields of the struct.
+	Fields []ProtoStructField
+	// Nested reports whether the message is declared in another message.
+	Nested bool
+}
+
+// ProtoStructField is a field of a ProtoStruct.
+type ProtoStructField struct {
+	// Name is the unexported name of the field, such as baseURL.
+	Name string
+	// Getter is the name of the exported accessor of the field, such as
+	// BaseURL.
+	Getter string
+	// Type is the Go type of the field.
+	Type string
+	// Tag is the option tag of the field, empty when none is needed.
+	Tag string
+	// Doc is the doc comment of the field.
+	Doc string
+}
+
+// protoScalars maps the scalar types of proto to Go types.
+var protoScalars = map[string]string{
+	"double": "float64", "float": "float32",
+	"int32": "int32", "sint32": "int32", "sfixed32": "int32",
+	"int64": "int64", "sint64": "int64", "sfixed64": "int64",
+	"uint32": "uint32", "fixed32": "uint32",
+	"uint64": "uint64", "fixed64": "uint64",
+	"bool": "bool", "string": "string", "bytes": "[]byte",
+	"google.protobuf.Duration":  "time.Duration",
+	"google.protobuf.Timestamp": "time.Time",
+}
+
+// Structs returns the Go structs of the messages of f. Message fields hold
+// the struct of the message, so their fields get nested options. Enum
+// fields are strings with an enum item, the values lower cased without
+// the prefix of the enum name, such as json for FORMAT_JSON.
+func (f *ProtoFile) Structs() ([]ProtoStruct, error) {
+	var result []ProtoStruct
+	for _, m := range f.Messages {
+		s := ProtoStruct{Name: ProtoTypeName(m.FullName), Message: m.FullName, Doc: m.Doc, Nested: m.Nested}
+		s.Receiver = receiverName(s.Name)
+		if s.Doc == "" {
+			s.Doc = fmt.Sprintf("%s is generated from the message %s of %s.", s.Name, m.FullName, f.Name)
+		}
+		for _, pf := range m.Fields {
+			field, err := f.structField(m, pf)
+			if err != nil {
+				return nil, fmt.Errorf("message %s: %w", m.FullName, err)
+			}
+			s.Fields = append(s.Fields, field)
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (f *ProtoFile) structField(m *ProtoMessage, pf ProtoField) (ProtoStructField, error) {
+	name := protoFieldName(pf.Name)
+	base := camel(pf.Name)
+	var items []string
+	if token.IsKeyword(name) || base != upperFirst(name) {
+		// Options such as WithIDToken keep the initialisms.
+		items = append(items, "name="+base)
+	}
+	if token.IsKeyword(name) {
+		name += "_"
+	}
+	if fields.SnakeCase(name) != pf.Name {
+		items = append(items, "key="+pf.Name)
+	}
+	typ, enum, err := f.goType(m, pf.Type)
+	if err != nil {
+		return ProtoStructField{}, fmt.Errorf("field %s: %w", pf.Name, err)
+	}
+	switch {
+	case pf.KeyType != "":
+		key, _, err := f.goType(m, pf.KeyType)
+		if err != nil {
+			return ProtoStructField{}, fmt.Errorf("field %s: %w", pf.Name, err)
+		}
+		typ = "map[" + key + "]" + typ
+	case pf.Repeated:
+		typ = "[]" + typ
+	case typ == "[]byte":
+		items = append(items, "item=-")
+	case enum != nil:
+		items = append(items, "enum="+strings.Join(enumValues(enum), "|"))
+	}
+	if pf.OneOf != "" {
+		items = append(items, "oneof="+pf.OneOf)
+	}
+	if pf.Deprecated {
+		items = append(items, "deprecated=deprecated in "+f.Name)
+	}
+	field := ProtoStructField{Name: name, Getter: base, Type: typ, Doc: pf.Doc}
+	if len(items) > 0 {
+		field.Tag = "`" + fields.TagName + ":" + strconv.Quote(strings.Join(items, ",")) + "`"
+	}
+	return field, nil
+}
+
+// goType resolves the proto type name, referenced in m, to a Go type,
+// looking up messages and enums from the innermost scope outwards like
+// protoc. It returns the enum for enum types.
+func (f *ProtoFile) goType(m *ProtoMessage, name string) (string, *ProtoEnum, error) {
+	if typ, ok := protoScalars[strings.TrimPrefix(name, ".")]; ok {
+		return typ, nil, nil
+	}
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "."), f.Package+".")
+	scope := m.FullName
+	for {
+		full := name
+		if scope != "" {
+			full = scope + "." + name
+		}
+		for _, msg := range f.Messages {
+			if msg.FullName == full {
+				return ProtoTypeName(full), nil, nil
+			}
+		}
+		for _, e := range f.Enums {
+			if e.FullName == full {
+				return "string", e, nil
+			}
+		}
+		if scope == "" {
+			return "", nil, fmt.Errorf("unknown type %s", name)
+		}
+		i := strings.LastIndexByte(scope, '.')
+		scope = scope[:max(i, 0)]
+	}
+}
+
+// enumValues returns the values of e for the enum item, such as json for
+// FORMAT_JSON of the enum Format.
+func enumValues(e *ProtoEnum) []string {
+	prefix := strings.ToUpper(fields.SnakeCase(e.FullName[strings.LastIndexByte(e.FullName, '.')+1:])) + "_"
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = strings.ToLower(strings.TrimPrefix(v, prefix))
+	}
+	return values
+}
+
+// ProtoTypeName returns the Go name of the struct generated for a message,
+// joining the names of nested messages such as ServerRetry for
+// Server.Retry.
+func ProtoTypeName(fullName string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(fullName, ".") {
+		b.WriteString(upperFirst(part))
+	}
+	return b.String()
+}
+
+// protoFieldName returns the unexported Go name of a field, such as
+// baseURL for base_url.
+func protoFieldName(name string) string {
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		switch {
+		case i == 0:
+			words[i] = strings.ToLower(w)
+		case initialisms[strings.ToLower(w)]:
+			words[i] = strings.ToUpper(w)
+		default:
+			words[i] = upperFirst(w)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// ProtoData is passed to the template generating the structs of a .proto
+// file.
+type ProtoData struct {
+	File    *ProtoFile
+	Package string
+	Imports []Import
+	Structs []ProtoStruct
+}
+
+// GenerateProto renders the Go structs of the messages of f as formatted Go
+// source of the package pkg. Options are generated for them like for
+// handwritten structs, see Parse.
+func GenerateProto(f *ProtoFile, pkg string) ([]byte, error) {
+	structs, err := f.Structs()
+	if err != nil {
+		return nil, err
+	}
+	data := ProtoData{File: f, Package: pkg, Structs: structs}
+	for _, s := range structs {
+		for _, field := range s.Fields {
+			if strings.Contains(field.Type, "time.") {
+				data.Imports = []Import{{Name: "time", Path: "time"}}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, "proto.tmpl", data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// PackageName returns the Go package name for f: the name after the
+// semicolon of go_package, its last path element, or the last element of
+// the proto package.
+func (f *ProtoFile) PackageName() string {
+	if f.GoPackage != "" {
+		if _, name, ok := strings.Cut(f.GoPackage, ";"); ok {
+			return name
+		}
+		return filepath.Base(f.GoPackage)
+	}
+	if f.Package != "" {
+		parts := strings.Split(f.Package, ".")
+		return strings.ReplaceAll(parts[len(parts)-1], "-", "_")
+	}
+	return strings.TrimSuffix(f.Name, ".proto")
+}
@@ -0,0 +1,18 @@
+package optiongen
+
+import "testing"
+
+func TestProtoNames(t *testing.T) {
+	types := map[string]string{"Server": "Server", "Server.Retry": "ServerRetry", "a.b.c": "ABC"}
+	for fullName, want := range types {
+		if got := ProtoTypeName(fullName); got != want {
+			t.Errorf("ProtoTypeName(%q) = %q, want %q", fullName, got, want)
+		}
+	}
+	fields := map[string]string{"host": "host", "base_url": "baseURL", "max_attempts": "maxAttempts", "id": "id"}
+	for name, want := range fields {
+		if got := protoFieldName(name); got != want {
+			t.Errorf("protoFieldName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
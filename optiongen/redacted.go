@@ -0,0 +1,70 @@
+package optiongen
+
+import (
+	"slices"
+	"strings"
+)
+
+// Redacted is a struct that gets the String and LogValue methods generated
+// by GenerateRedacted.
+type Redacted struct {
+	// Name is the name of the struct.
+	Name string
+	// TypeArgs are those of a generic struct.
+	TypeArgs string
+	// Receiver is the variable name the generated code uses for the struct.
+	Receiver string
+	// Fields are the fields that options are generated for.
+	Fields []Field
+}
+
+// Format returns the fmt format of the String method, such as
+// {baseURL:%+v header:***}, formatting the fields that are not sensitive.
+func (r Redacted) Format() string {
+	parts := make([]string, len(r.Fields))
+	for i, f := range r.Fields {
+		value := "%+v"
+		if f.Sensitive {
+			value = redactedValue
+		}
+		parts[i] = f.Name + ":" + value
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+// redactedValue replaces the values of sensitive fields.
+const redactedValue = "***"
+
+// RedactedValue returns the text replacing the values of sensitive fields.
+func (s *Struct) RedactedValue() string {
+	return redactedValue
+}
+
+// Sensitive reports whether a field of s or of a nested struct is tagged
+// `option:"sensitive"`.
+func (s *Struct) Sensitive() bool {
+	return sensitive(s.Fields)
+}
+
+func sensitive(fields []Field) bool {
+	return slices.ContainsFunc(fields, func(f Field) bool { return f.Sensitive || sensitive(f.Nested) })
+}
+
+// Redacted returns the struct and the nested structs holding sensitive
+// fields, which get String and LogValue methods masking them. Nested
+// structs without sensitive fields are formatted as they are.
+func (s *Struct) Redacted() []Redacted {
+	result := []Redacted{{Name: s.Name, TypeArgs: s.TypeArgs, Receiver: s.Receiver, Fields: s.Fields}}
+	var walk func(fields []Field)
+	walk = func(fields []Field) {
+		for _, f := range fields {
+			if f.Struct == "" || !sensitive(f.Nested) || slices.ContainsFunc(result, func(r Redacted) bool { return r.Name == f.Struct }) {
+				continue
+			}
+			result = append(result, Redacted{Name: f.Struct, Receiver: receiverName(f.Struct), Fields: f.Nested})
+			walk(f.Nested)
+		}
+	}
+	walk(s.Fields)
+	return result
+}
@@ -0,0 +1,143 @@
+package optiongen
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+// Coverage describes how far the configuration surface of a struct is
+// covered by options, defaults and tests, see Cover.
+type Coverage struct {
+	// Struct is the name of the struct.
+	Struct string
+	// Fields are the fields of the struct and of its nested structs in
+	// declaration order, followed by the fields tagged `option:"-"`.
+	Fields []FieldCoverage
+}
+
+// FieldCoverage is the coverage of a single field.
+type FieldCoverage struct {
+	// Path is the field name, qualified by the fields holding a nested
+	// struct such as retry.maxAttempts.
+	Path string
+	// Option is the name of the option of the field, empty for fields
+	// tagged `option:"-"`.
+	Option string
+	// Declared reports whether the package declares Option, as generated
+	// or handwritten code.
+	Declared bool
+	// Default is the default value from the default tag, if any.
+	Default string
+	// Tested reports whether a test file of the package refers to Option.
+	Tested bool
+}
+
+// Counts returns the number of fields, of those with a declared option,
+// with a default and with a tested option.
+func (c *Coverage) Counts() (total, declared, defaults, tested int) {
+	for _, f := range c.Fields {
+		total++
+		if f.Declared {
+			declared++
+		}
+		if f.Default != "" {
+			defaults++
+		}
+		if f.Tested {
+			tested++
+		}
+	}
+	return total, declared, defaults, tested
+}
+
+// Cover reports the coverage of s, parsed from the package in the first of
+// dirs. An option counts as declared when a function of its name exists in
+// one of the packages in dirs, such as the package declaring the struct and
+// the sub-package of Pkg, and as tested when one of their _test.go files
+// refers to it. Options are named as in the generated code, following the
+// name block, Prefix and Style of s.
+func Cover(s *Struct, dirs ...string) (*Coverage, error) {
+	set, err := s.templateSet()
+	if err != nil {
+		return nil, err
+	}
+	if s, err = s.renamed(set); err != nil {
+		return nil, err
+	}
+
+	funcs, used := map[string]bool{}, map[string]bool{}
+	var excluded []string
+	for i, dir := range dirs {
+		pkgs, err := parseDir(token.NewFileSet(), dir, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		declaring := i == 0
+		for _, pkg := range pkgs {
+			for j, file := range pkg.Files {
+				if !notTest(pkg.Paths[j]) {
+					ast.Inspect(file, func(n ast.Node) bool {
+						if id, ok := n.(*ast.Ident); ok {
+							used[id.Name] = true
+						}
+						return true
+					})
+					continue
+				}
+				for _, decl := range file.Decls {
+					if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+						funcs[fn.Name.Name] = true
+					}
+				}
+				if _, st := findStruct(file, s.Name); st != nil && declaring && pkg.Name == s.Package {
+					excluded = excludedFields(st)
+				}
+			}
+		}
+	}
+
+	c := &Coverage{Struct: s.Name}
+	var walk func(path string, fields []Field)
+	walk = func(path string, fields []Field) {
+		for _, f := range fields {
+			name := path + f.Name
+			if !f.Embedded {
+				c.Fields = append(c.Fields, FieldCoverage{
+					Path:     name,
+					Option:   f.Option,
+					Declared: funcs[f.Option],
+					Default:  f.Default,
+					Tested:   used[f.Option],
+				})
+			}
+			if f.Embedded {
+				walk(path, f.Nested)
+			} else {
+				walk(name+".", f.Nested)
+			}
+		}
+	}
+	walk("", s.Fields)
+	for _, name := range excluded {
+		c.Fields = append(c.Fields, FieldCoverage{Path: name})
+	}
+	return c, nil
+}
+
+// excludedFields returns the names of the fields of st tagged
+// `option:"-"`.
+func excludedFields(st *ast.StructType) []string {
+	var names []string
+	for _, f := range st.Fields.List {
+		raw, err := structTag(f.Tag)
+		if err != nil || !fields.ParseTag(raw.Get(fields.TagName)).Skip {
+			continue
+		}
+		for _, name := range f.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
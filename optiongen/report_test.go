@@ -0,0 +1,44 @@
+package optiongen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCover(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"server.go":       "package app\n\ntype Server struct {\n\thost string `default:\"localhost\"`\n\tport int\n\tlimits Limits\n\tlog func(string) `option:\"-\"`\n}\n\ntype Limits struct {\n\tmaxConns int\n}\n",
+		"options.go":      "package app\n\nfunc WithHost(host string) {}\n\nfunc WithLimitsMaxConns(n int) {}\n",
+		"options_test.go": "package app\n\nvar _ = WithHost\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := Parse(dir, "Server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Cover(s, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Coverage{Struct: "Server", Fields: []FieldCoverage{
+		{Path: "host", Option: "WithHost", Declared: true, Default: `"localhost"`, Tested: true},
+		{Path: "port", Option: "WithPort"},
+		{Path: "limits", Option: "WithLimits"},
+		{Path: "limits.maxConns", Option: "WithLimitsMaxConns", Declared: true},
+		{Path: "log"},
+	}}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Cover() = %+v, want %+v", c, want)
+	}
+	total, declared, defaults, tested := c.Counts()
+	if total != 5 || declared != 2 || defaults != 1 || tested != 1 {
+		t.Errorf("Counts() = %d, %d, %d, %d, want 5, 2, 1, 1", total, declared, defaults, tested)
+	}
+}
@@ -0,0 +1,63 @@
+package optiongen
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// StringOption is the variant of the option of a duration field, or of a
+// field tagged `option:"size"`, taking the value in its string form, such
+// as WithTimeoutString("30s") next to WithTimeout.
+type StringOption struct {
+	Struct *Struct
+	// Name is the name of the option, the name of Option followed by
+	// String.
+	Name string
+	// Option is the option the parsed value is passed to.
+	Option string
+	// Param is the parameter name of the option.
+	Param string
+	// Parse is the function of the options package parsing the string,
+	// such as options.ParseDuration.
+	Parse string
+	// Example is a string form for the doc comment, such as "30s".
+	Example string
+}
+
+// StringForm reports whether the option of f gets a string variant, see
+// StringOption.
+func (f Field) StringForm() bool {
+	return f.Type == "time.Duration" || f.Size
+}
+
+// StringOption returns the string variant of the option named option for
+// f, nil if f is neither a duration nor tagged `option:"size"`.
+func (s *Struct) StringOption(option string, f Field) (*StringOption, error) {
+	o := &StringOption{Struct: s, Name: option + "String", Option: option, Param: f.Param}
+	switch {
+	case f.Type == "time.Duration":
+		o.Parse, o.Example = "options.ParseDuration", `"30s"`
+	case f.Size:
+		typ, err := s.Ref(f.Type)
+		if err != nil {
+			return nil, err
+		}
+		o.Parse, o.Example = "options.ParseSize["+typ+"]", `"64MiB"`
+	default:
+		return nil, nil
+	}
+	return o, nil
+}
+
+// size checks the size item of the option tag on f, which is only valid
+// on integer fields or types declared in the package.
+func size(f *Field) error {
+	named := !strings.ContainsAny(f.Type, ".[]*(){} ") && types.Universe.Lookup(f.Type) == nil
+	if (!slices.Contains(integers, f.Type) || f.Type == "time.Duration") && !named {
+		return fmt.Errorf("size needs an integer, got %s", f.Type)
+	}
+	f.Size = true
+	return nil
+}
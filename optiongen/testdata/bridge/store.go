@@ -0,0 +1,39 @@
+package bridge
+
+import "errors"
+
+// Store has setters for some of its fields, which the bridged options call.
+type Store struct {
+	name   string
+	labels map[string]string
+	limit  int
+	tags   []string
+}
+
+// SetName sets the name without a result.
+func (s *Store) SetName(name string) {
+	s.name = name
+}
+
+// SetLabels returns an error only.
+func (s *Store) SetLabels(labels map[string]string) error {
+	if labels == nil {
+		return errors.New("labels must not be nil")
+	}
+	s.labels = labels
+	return nil
+}
+
+// SetLimit returns the store as well.
+func (s *Store) SetLimit(limit int) (*Store, error) {
+	if limit < 0 {
+		return nil, errors.New("limit must not be negative")
+	}
+	s.limit = limit
+	return s, nil
+}
+
+// SetTags takes another type than the field and is no setter.
+func (s *Store) SetTags(tags ...string) {
+	s.tags = tags
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// Client sends requests to a service.
+type Client struct {
+	// baseURL is the URL all requests are resolved against.
+	baseURL string `option:"required,short=u" validate:"url"`
+	// header is sent with every request.
+	header map[string]string `option:"sensitive"`
+	// timeout bounds each request.
+	timeout time.Duration `default:"30s"`
+	// maxBody limits the size of response bodies.
+	maxBody int64 `option:"size" default:"64MiB"`
+	retry   Retry
+	// baseClient sends the requests.
+	baseClient *http.Client
+	// proxy is the URL of a proxy for all requests.
+	proxy string `option:"deprecated=use WithBaseClient"`
+}
+
+// Retry controls how failed requests are repeated.
+type Retry struct {
+	// maxAttempts is the number of attempts including the first one.
+	maxAttempts int `validate:"min=1" default:"3"`
+}
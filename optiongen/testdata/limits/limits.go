@@ -0,0 +1,10 @@
+package limits
+
+// Limits bounds the resources of a request, in sizes of several integer
+// types.
+type Limits struct {
+	maxBody   int64  `option:"size"`
+	maxHeader uint32 `option:"size"`
+	chunk     int    `option:"size"`
+	retries   int
+}
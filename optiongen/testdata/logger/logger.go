@@ -0,0 +1,7 @@
+package logger
+
+// Logger writes log records.
+type Logger interface {
+	Info(msg string, args ...any)
+	Enabled(level int) bool
+}
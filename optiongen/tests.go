@@ -0,0 +1,241 @@
+package optiongen
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Tests are the cases of the tests generated by GenerateTests.
+type Tests struct {
+	// Valid are options expected to set their field.
+	Valid []TestCase
+	// Invalid are options expected to fail validation.
+	Invalid []TestCase
+	// Imports are the packages referenced by the values of the cases.
+	Imports []Import
+}
+
+// TestCase calls an option with a value.
+type TestCase struct {
+	// Name is the name of the subtest.
+	Name string
+	// Option is the name of the option.
+	Option string
+	// Value is the Go expression passed to the option.
+	Value string
+	// Path selects the field set by the option, relative to the receiver.
+	Path string
+}
+
+// Tests derives the test cases for the options of s. Fields whose type has
+// no obvious sample value, such as interfaces and pointers, are left out.
+func (s *Struct) Tests() Tests {
+	var t Tests
+	used := map[string]bool{}
+	add := func(f Field, path string) {
+		valid, ok := sample(f)
+		if !ok {
+			return
+		}
+		t.Valid = append(t.Valid, TestCase{Name: f.Option, Option: f.Option, Value: valid, Path: path})
+		for _, c := range f.Checks {
+			if value, ok := violate(f, c); ok {
+				t.Invalid = append(t.Invalid, TestCase{Name: f.Option + "/" + c.Rule, Option: f.Option, Value: value})
+			}
+		}
+		for _, pkg := range f.Packages {
+			used[pkg] = true
+		}
+	}
+	for _, f := range s.Fields {
+		add(f, f.Name)
+	}
+	for _, o := range s.NestedOptions() {
+		path := ""
+		for _, g := range o.Getters {
+			path += g.Field + "."
+		}
+		add(o.Field, path+o.Name)
+	}
+
+	for _, i := range slices.Concat(s.Imports, s.NestedImports) {
+		if used[i.Name] && !slices.Contains(t.Imports, i) {
+			t.Imports = append(t.Imports, i)
+		}
+	}
+	return t
+}
+
+var (
+	integers = []string{"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune", "time.Duration"}
+	floats   = []string{"float32", "float64"}
+)
+
+// sample returns a value for f that passes its checks.
+func sample(f Field) (string, bool) {
+	rules := map[string]string{}
+	for _, c := range f.Checks {
+		rules[c.Rule] = c.Arg
+	}
+	if len(f.Enum) > 0 {
+		return strconv.Quote(f.Enum[0]), true
+	}
+	if arg, ok := rules["oneof"]; ok {
+		if f.Type == "string" {
+			return strconv.Quote(strings.Fields(arg)[0]), true
+		}
+		return scalar(f.Type, strings.Fields(arg)[0])
+	}
+	if _, ok := rules["url"]; ok {
+		return `"https://example.com"`, true
+	}
+	if _, ok := rules["email"]; ok {
+		return `"user@example.com"`, true
+	}
+
+	if _, ok := elem(f.Type); ok || f.Type == "string" {
+		n := 1
+		if arg, ok := rules["min"]; ok {
+			n = max(n, atoi(arg))
+		}
+		if arg, ok := rules["max"]; ok {
+			n = min(n, atoi(arg))
+		}
+		if arg, ok := rules["len"]; ok {
+			n = atoi(arg)
+		}
+		return sized(f.Type, n)
+	}
+
+	v := 1.0
+	if arg, ok := rules["min"]; ok {
+		v, _ = strconv.ParseFloat(arg, 64)
+	}
+	if arg, ok := rules["max"]; ok {
+		if bound, _ := strconv.ParseFloat(arg, 64); v > bound {
+			v = bound
+		}
+	}
+	return scalar(f.Type, strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+// violate returns a value for f that fails c.
+func violate(f Field, c Check) (string, bool) {
+	_, collection := elem(f.Type)
+	sizedType := collection || f.Type == "string"
+	switch c.Rule {
+	case "required":
+		return "*new(" + f.ParamType() + ")", true
+	case "enum":
+		return strconv.Quote(strings.Join(f.Enum, "") + "_"), true
+	case "url":
+		return `"not a url"`, true
+	case "email":
+		return `"not a mail address"`, true
+	case "oneof":
+		values := strings.Fields(c.Arg)
+		if f.Type == "string" {
+			return strconv.Quote(strings.Join(values, "") + "_"), true
+		}
+		highest := 0.0
+		for _, v := range values {
+			n, _ := strconv.ParseFloat(v, 64)
+			highest = max(highest, n)
+		}
+		return scalar(f.Type, strconv.FormatFloat(highest+1, 'f', -1, 64))
+	case "min", "max", "len":
+		bound, err := strconv.ParseFloat(c.Arg, 64)
+		if err != nil {
+			return "", false
+		}
+		if c.Rule == "min" {
+			bound--
+		} else {
+			bound++
+		}
+		if sizedType {
+			if bound < 0 {
+				return "", false
+			}
+			return sized(f.Type, int(bound))
+		}
+		if bound < 0 && strings.HasPrefix(f.Type, "u") {
+			return "", false
+		}
+		return scalar(f.Type, strconv.FormatFloat(bound, 'f', -1, 64))
+	}
+	return "", false
+}
+
+// scalar converts the number or bool literal lit to typ.
+func scalar(typ, lit string) (string, bool) {
+	switch {
+	case typ == "bool":
+		return "true", true
+	case slices.Contains(integers, typ):
+		if strings.Contains(lit, ".") {
+			return "", false
+		}
+		return typ + "(" + lit + ")", true
+	case slices.Contains(floats, typ):
+		return typ + "(" + lit + ")", true
+	}
+	return "", false
+}
+
+// sized returns a string, slice or map of typ with n elements.
+func sized(typ string, n int) (string, bool) {
+	if typ == "string" {
+		return strconv.Quote(strings.Repeat("a", n)), true
+	}
+	e, ok := elem(typ)
+	if !ok {
+		return "", false
+	}
+	items := make([]string, n)
+	for i := range items {
+		items[i] = e(i)
+	}
+	return typ + "{" + strings.Join(items, ", ") + "}", true
+}
+
+// elem returns a function producing the i-th element of a literal of typ
+// when typ is a slice or map of basic types.
+func elem(typ string) (func(i int) string, bool) {
+	if e, ok := strings.CutPrefix(typ, "[]"); ok {
+		value, ok := untyped(e)
+		return func(int) string { return value }, ok
+	}
+	if kv, ok := strings.CutPrefix(typ, "map["); ok {
+		k, v, _ := strings.Cut(kv, "]")
+		value, ok := untyped(v)
+		switch {
+		case !ok:
+		case k == "string":
+			return func(i int) string { return fmt.Sprintf("%q: %s", "k"+strconv.Itoa(i), value) }, true
+		case slices.Contains(integers, k):
+			return func(i int) string { return fmt.Sprintf("%d: %s", i, value) }, true
+		}
+	}
+	return nil, false
+}
+
+// untyped returns an untyped constant assignable to typ.
+func untyped(typ string) (string, bool) {
+	switch {
+	case typ == "string":
+		return `"a"`, true
+	case typ == "bool":
+		return "true", true
+	case slices.Contains(integers, typ), slices.Contains(floats, typ):
+		return "1", true
+	}
+	return "", false
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
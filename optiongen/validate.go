@@ -0,0 +1,106 @@
+package optiongen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Check is a validation of the value passed to a generated option, derived
+// from the `validate` tag of the field. The tag holds comma separated rules:
+//
+//	required    the value must not be the zero value
+//	min=N       numbers must be at least N, strings, slices and maps must
+//	            have a length of at least N
+//	max=N       like min, but an upper bound
+//	len=N       the length must be exactly N
+//	oneof=a b   the value must be one of the space separated values
+//	url         the string must be an absolute URL
+//	email       the string must be a mail address
+type Check struct {
+	// Rule is the name of the rule, such as min.
+	Rule string
+	// Arg is the argument of the rule, such as 1 for min=1.
+	Arg string
+	// Init is an optional statement run before Cond.
+	Init string
+	// Cond is the expression reporting an invalid value.
+	Cond string
+	// Format is the fmt format of the error, formatting the value.
+	Format string
+	// Imports are the import paths needed by Init and Cond.
+	Imports []string
+}
+
+// parseChecks translates the validate tag of f into checks.
+func parseChecks(f Field, tag string) ([]Check, error) {
+	if tag == "" {
+		return nil, nil
+	}
+	v := f.Param
+	sized := strings.HasPrefix(f.Type, "[]") || strings.HasPrefix(f.Type, "map[") || f.Type == "string"
+
+	verb := "%v"
+	if f.Type == "string" {
+		verb = "%q"
+	}
+
+	var checks []Check
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		check := Check{Rule: name, Arg: arg, Imports: []string{"fmt"}}
+		switch name {
+		case "required":
+			check.Cond = "options.IsZero(" + v + ")"
+			check.Format = "must be set"
+		case "min", "max", "len":
+			if _, err := strconv.ParseFloat(arg, 64); err != nil {
+				return nil, fmt.Errorf("validate rule %s needs a number, got %q", name, arg)
+			}
+			op := map[string]string{"min": "<", "max": ">", "len": "!="}[name]
+			bound := map[string]string{"min": "at least", "max": "at most", "len": "exactly"}[name]
+			switch {
+			case sized:
+				check.Cond = fmt.Sprintf("len(%s) %s %s", v, op, arg)
+				check.Format = fmt.Sprintf("must have a length of %s %s", bound, arg)
+			case name == "len":
+				return nil, fmt.Errorf("validate rule len needs a string, slice or map, got %s", f.Type)
+			default:
+				check.Cond = fmt.Sprintf("%s %s %s", v, op, arg)
+				check.Format = fmt.Sprintf("must be %s %s", bound, arg)
+			}
+		case "oneof":
+			values := strings.Fields(arg)
+			if len(values) == 0 {
+				return nil, fmt.Errorf("validate rule oneof needs values")
+			}
+			conds := make([]string, len(values))
+			for i, value := range values {
+				if f.Type == "string" {
+					value = strconv.Quote(value)
+				}
+				conds[i] = v + " != " + value
+			}
+			check.Cond = strings.Join(conds, " && ")
+			check.Format = "must be one of " + strings.Join(values, ", ")
+		case "url":
+			check.Init = "u, err := url.Parse(" + v + ")"
+			check.Cond = `err != nil || u.Scheme == "" || u.Host == ""`
+			check.Format = "must be an absolute URL"
+			check.Imports = append(check.Imports, "net/url")
+		case "email":
+			check.Init = "_, err := mail.ParseAddress(" + v + ")"
+			check.Cond = "err != nil"
+			check.Format = "must be a mail address"
+			check.Imports = append(check.Imports, "net/mail")
+		default:
+			return nil, fmt.Errorf("unknown validate rule %q", name)
+		}
+		if (name == "url" || name == "email") && f.Type != "string" {
+			return nil, fmt.Errorf("validate rule %s needs a string, got %s", name, f.Type)
+		}
+		check.Format = strings.ReplaceAll(f.Name+" "+check.Format, "%", "%%") + ", got " + verb
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
@@ -0,0 +1,19 @@
+package options
+
+import "github.com/StevenCyb/golang-functional-options/internal/fields"
+
+// CloneWith returns a deep copy of src with opts applied, so a configured
+// value can serve as a prototype for variants, for example the same client
+// with a different base URL. The copy follows the same rules as ApplyTx,
+// and the BeforeApply hook is skipped as the copy is already configured.
+func CloneWith[T any](src *T, opts ...Option[T]) *T {
+	return apply(fields.Copy(src), false, true, opts)
+}
+
+// ApplyCopy applies opts to a deep copy of seed and returns the result, so
+// the pattern also works for immutable value type configs. seed and the maps
+// and slices it holds are never modified. Like CloneWith it skips the
+// BeforeApply hook.
+func ApplyCopy[T any](seed T, opts ...Option[T]) T {
+	return *apply(fields.Copy(&seed), false, true, opts)
+}
@@ -0,0 +1,48 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestCloneWith(t *testing.T) {
+	tests := []struct {
+		name string
+		src  server
+		opts []options.Option[server]
+		want server
+	}{
+		{name: "plain copy", src: server{host: "a", tags: []string{"x"}}, want: server{host: "a", tags: []string{"x"}}},
+		{name: "overridden", src: server{host: "a", port: 80, tags: []string{"x"}}, opts: []options.Option[server]{withHost("b")}, want: server{host: "b", port: 80, tags: []string{"x"}}},
+		{name: "slices are copied", src: server{tags: []string{"x"}}, opts: []options.Option[server]{withTag("y")}, want: server{tags: []string{"x", "y"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Spare capacity lets an append on a shared slice go unnoticed
+			// by comparing src alone.
+			src := tt.src
+			src.tags = append(make([]string, 0, len(tt.src.tags)+1), tt.src.tags...)
+
+			got := options.CloneWith(&src, tt.opts...)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("CloneWith() = %+v, want %+v", *got, tt.want)
+			}
+			if src.host != tt.src.host || src.port != tt.src.port || !reflect.DeepEqual(src.tags[:cap(src.tags)], append(tt.src.tags, "")) {
+				t.Errorf("CloneWith() modified src to %+v, want %+v", src, tt.src)
+			}
+		})
+	}
+}
+
+func TestApplyCopy(t *testing.T) {
+	seed := server{host: "a", tags: append(make([]string, 0, 2), "x")}
+	got := options.ApplyCopy(seed, withHost("b"), withTag("y"))
+	if want := (server{host: "b", tags: []string{"x", "y"}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyCopy() = %+v, want %+v", got, want)
+	}
+	if seed.host != "a" || seed.tags[:2][1] != "" {
+		t.Errorf("ApplyCopy() modified the seed to %+v", seed)
+	}
+}
@@ -0,0 +1,17 @@
+package options
+
+// Combine bundles several options into a single one that applies them in
+// the given order.
+func Combine[T any](opts ...Option[T]) Option[T] {
+	return func(t *T) {
+		Apply(t, opts...)
+	}
+}
+
+// CombineE bundles several fallible options into a single one that applies
+// them in the given order and reports all of their errors, see ApplyE.
+func CombineE[T any](opts ...OptionE[T]) OptionE[T] {
+	return func(t *T) error {
+		return ApplyE(t, opts...)
+	}
+}
@@ -0,0 +1,51 @@
+package options_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestCombine(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []options.Option[server]
+		want server
+	}{
+		{name: "empty", want: server{}},
+		{name: "in order", opts: []options.Option[server]{setHost("a"), addTag("x"), setHost("b")}, want: server{host: "b", tags: []string{"x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := options.Apply(new(server), options.Combine(tt.opts...))
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Apply(Combine()) = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombineE(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []options.OptionE[server]
+		want    server
+		wantErr error
+	}{
+		{name: "valid", opts: []options.OptionE[server]{options.E(setHost("a")), setPort(80)}, want: server{host: "a", port: 80}},
+		{name: "invalid", opts: []options.OptionE[server]{options.E(setHost("a")), setPort(-1)}, want: server{host: "a"}, wantErr: errPort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(server)
+			if err := options.ApplyE(got, options.CombineE(tt.opts...)); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApplyE(CombineE()) error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ApplyE(CombineE()) = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package options
+
+// If returns opt when cond is true and an option that does nothing otherwise.
+func If[T any](cond bool, opt Option[T]) Option[T] {
+	if cond {
+		return NotNil(opt)
+	}
+	return noop[T]
+}
+
+// Unless returns opt when cond is false and an option that does nothing
+// otherwise.
+func Unless[T any](cond bool, opt Option[T]) Option[T] {
+	return If(!cond, opt)
+}
+
+// IfFunc applies opt only when pred reports true for the target at the time
+// the option is applied.
+func IfFunc[T any](pred func(*T) bool, opt Option[T]) Option[T] {
+	opt = NotNil(opt)
+	return func(t *T) {
+		if pred(t) {
+			opt(t)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package options_test
+
+import (
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestConditional(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  options.Option[server]
+		host string
+		want string
+	}{
+		{name: "If true", opt: options.If(true, setHost("a")), want: "a"},
+		{name: "If false", opt: options.If(false, setHost("a")), want: ""},
+		{name: "Unless true", opt: options.Unless(true, setHost("a")), want: ""},
+		{name: "Unless false", opt: options.Unless(false, setHost("a")), want: "a"},
+		{
+			name: "IfFunc matching",
+			opt:  options.IfFunc(func(s *server) bool { return s.host == "" }, setHost("a")),
+			want: "a",
+		},
+		{
+			name: "IfFunc against the current state",
+			opt:  options.IfFunc(func(s *server) bool { return s.host == "" }, setHost("a")),
+			host: "b",
+			want: "b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := options.Apply(&server{host: tt.host}, tt.opt)
+			if got.host != tt.want {
+				t.Errorf("host = %q, want %q", got.host, tt.want)
+			}
+		})
+	}
+}
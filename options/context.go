@@ -0,0 +1,24 @@
+package options
+
+import "context"
+
+// contextKey is the key of the option set for T in a context.
+type contextKey[T any] struct{}
+
+// NewContext returns a copy of ctx carrying set for T after the options
+// already carried, so middleware can attach per-request overrides such as
+// timeouts or headers for the constructors called further down:
+//
+//	ctx = options.NewContext(r.Context(), options.NewSet(WithTimeout(time.Second)))
+func NewContext[T any](ctx context.Context, set OptionSet[T]) context.Context {
+	return context.WithValue(ctx, contextKey[T]{}, FromContext[T](ctx).Merge(set))
+}
+
+// FromContext returns the options for T carried by ctx, nil if there are
+// none. Applied after the options of the caller, they override them:
+//
+//	client, err := New(baseURL, append(opts, options.FromContext[Client](ctx)...)...)
+func FromContext[T any](ctx context.Context) OptionSet[T] {
+	set, _ := ctx.Value(contextKey[T]{}).(OptionSet[T])
+	return set.Clone()
+}
@@ -0,0 +1,33 @@
+package options_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if got := options.FromContext[server](ctx); got != nil {
+		t.Errorf("FromContext() = %v, want nil", got)
+	}
+
+	ctx = options.NewContext(ctx, options.NewSet(setHost("a"), addTag("x")))
+	ctx = options.NewContext(ctx, options.NewSet(setHost("b")))
+	got := options.Apply(new(server), options.FromContext[server](ctx)...)
+	if want := (server{host: "b", tags: []string{"x"}}); !reflect.DeepEqual(*got, want) {
+		t.Errorf("Apply(FromContext()) = %+v, want %+v", *got, want)
+	}
+
+	// The carried set belongs to the context, changing the result does not
+	// change it.
+	options.FromContext[server](ctx)[2] = setHost("c")
+	if got := options.Apply(new(server), options.FromContext[server](ctx)...); got.host != "b" {
+		t.Errorf("FromContext() after a change sets host %q, want b", got.host)
+	}
+	if got := options.FromContext[registered](ctx); got != nil {
+		t.Errorf("FromContext() of another type = %v, want nil", got)
+	}
+}
@@ -0,0 +1,23 @@
+package options
+
+import "slices"
+
+// Defaults is an option set that is applied before any caller supplied
+// options.
+type Defaults[T any] []Option[T]
+
+// WithDefaults declares the default option set for T.
+func WithDefaults[T any](defaults ...Option[T]) Defaults[T] {
+	return Defaults[T](defaults)
+}
+
+// Apply runs the defaults followed by opts against target and returns it.
+func (d Defaults[T]) Apply(target *T, opts ...Option[T]) *T {
+	return ApplyWithDefaults(target, d, opts...)
+}
+
+// ApplyWithDefaults runs defaults followed by opts against target and
+// returns it, so caller supplied options always override the defaults.
+func ApplyWithDefaults[T any](target *T, defaults Defaults[T], opts ...Option[T]) *T {
+	return Apply(target, slices.Concat(defaults, Defaults[T](opts))...)
+}
@@ -0,0 +1,33 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestDefaults(t *testing.T) {
+	defaults := options.WithDefaults(setHost("localhost"), addTag("default"))
+	tests := []struct {
+		name string
+		opts []options.Option[server]
+		want server
+	}{
+		{name: "defaults only", want: server{host: "localhost", tags: []string{"default"}}},
+		{name: "overridden", opts: []options.Option[server]{setHost("example.com")}, want: server{host: "example.com", tags: []string{"default"}}},
+		{name: "added to", opts: []options.Option[server]{addTag("x")}, want: server{host: "localhost", tags: []string{"default", "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := defaults.Apply(new(server), tt.opts...)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", *got, tt.want)
+			}
+			got = options.ApplyWithDefaults(new(server), defaults, tt.opts...)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ApplyWithDefaults() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package options
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	deprecationHook atomic.Pointer[func(msg string)]
+	deprecationSeen sync.Map // map[string]struct{}
+)
+
+// SetDeprecationHook replaces the function called when a deprecated option
+// is applied. Passing nil restores the default, which logs every distinct
+// message once through the standard logger.
+func SetDeprecationHook(hook func(msg string)) {
+	if hook == nil {
+		deprecationHook.Store(nil)
+		return
+	}
+	deprecationHook.Store(&hook)
+}
+
+func warnDeprecated(msg string) {
+	if hook := deprecationHook.Load(); hook != nil {
+		(*hook)(msg)
+		return
+	}
+	if _, seen := deprecationSeen.LoadOrStore(msg, struct{}{}); !seen {
+		log.Printf("deprecated option: %s", msg)
+	}
+}
+
+// Deprecated marks opt as deprecated. The option is still applied but the
+// deprecation hook is invoked with msg, see SetDeprecationHook.
+//
+//	// Deprecated: use WithHeaders.
+//	func WithHeader(header map[string]string) options.Option[Client] {
+//		return options.Deprecated(WithHeaders(header), "WithHeader is deprecated, use WithHeaders")
+//	}
+func Deprecated[T any](opt Option[T], msg string) Option[T] {
+	opt = NotNil(opt)
+	return func(t *T) {
+		if s := scopeOf(t); s == nil || !s.probing {
+			warnDeprecated(msg)
+		}
+		opt(t)
+	}
+}
+
+// DeprecatedE is Deprecated for options that can fail.
+func DeprecatedE[T any](opt OptionE[T], msg string) OptionE[T] {
+	return func(t *T) error {
+		if s := scopeOf(t); s == nil || !s.probing {
+			warnDeprecated(msg)
+		}
+		if opt == nil {
+			return nil
+		}
+		return opt(t)
+	}
+}
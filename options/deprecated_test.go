@@ -0,0 +1,24 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestDeprecated(t *testing.T) {
+	var warnings []string
+	options.SetDeprecationHook(func(msg string) { warnings = append(warnings, msg) })
+	t.Cleanup(func() { options.SetDeprecationHook(nil) })
+
+	old := options.Deprecated(setHost("a"), "setHost is deprecated")
+	got := options.Apply(new(server), old, addTag("x"), old)
+	if got.host != "a" {
+		t.Errorf("host = %q, want %q", got.host, "a")
+	}
+	want := []string{"setHost is deprecated", "setHost is deprecated"}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %q, want %q", warnings, want)
+	}
+}
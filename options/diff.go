@@ -0,0 +1,28 @@
+package options
+
+import (
+	"reflect"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+// Change is a field that ends up with different values when two option sets
+// are applied. Field is the dotted path of the struct field.
+type Change struct {
+	Field string
+	A, B  any
+}
+
+// Diff applies a and b to separate zero values of T and reports every field
+// that differs between the results, which helps to find out why two
+// environments produce differently configured values. T must be a struct.
+func Diff[T any](a, b OptionSet[T]) []Change {
+	va := reflect.ValueOf(a.Apply(new(T))).Elem()
+	vb := reflect.ValueOf(b.Apply(new(T))).Elem()
+
+	var changes []Change
+	for _, d := range fields.Diff(va, vb) {
+		changes = append(changes, Change{Field: d.Path, A: d.A, B: d.B})
+	}
+	return changes
+}
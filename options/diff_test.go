@@ -0,0 +1,37 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b options.OptionSet[server]
+		want []options.Change
+	}{
+		{name: "equal", a: options.OptionSet[server]{setHost("a")}, b: options.OptionSet[server]{setHost("a")}},
+		{
+			name: "changed",
+			a:    options.OptionSet[server]{setHost("a"), addTag("x")},
+			b:    options.OptionSet[server]{setHost("b"), addTag("x")},
+			want: []options.Change{{Field: "host", A: "a", B: "b"}},
+		},
+		{
+			name: "nested",
+			a:    options.OptionSet[server]{func(s *server) { s.limits.maxConns = 1 }},
+			b:    options.OptionSet[server]{},
+			want: []options.Change{{Field: "limits.maxConns", A: 1, B: 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := options.Diff(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// Package options provides generic building blocks for the functional
+// options pattern, so a struct can be configured through `Option[T]`
+// values without re-declaring the option type and apply loop for every
+// type that uses it.
+//
+// # Struct tags
+//
+// Functions translating dynamic input into options, such as FromMap, match
+// input keys against the fields of the target struct, including unexported
+// ones. The key of a field is the snake case form of its name (baseURL
+// becomes base_url) unless it is set by the key item of the `option` tag.
+// A tag of "-" excludes the field:
+//
+//	type Client struct {
+//		baseURL    string `option:"key=url"`
+//		header     map[string]string
+//		baseClient *http.Client `option:"-"`
+//	}
+package options
@@ -0,0 +1,47 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OptionError is an error returned by an option, wrapped with the name of
+// the option that failed.
+type OptionError struct {
+	Name string
+	Err  error
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("option %s: %v", e.Name, e.Err)
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// optionError wraps err with the name of opt unless it already names the
+// option that failed, for example because it comes from a nested ApplyE.
+func optionError(opt any, err error) error {
+	var named *OptionError
+	if errors.As(err, &named) {
+		return err
+	}
+	return &OptionError{Name: optionName(opt), Err: err}
+}
+
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$`)
+
+// optionName derives a readable name such as WithLogger from the function
+// that built opt. The package path, closure suffixes and the [...] marking
+// the functions of generic options such as Duration are dropped.
+func optionName(opt any) string {
+	name := strings.ReplaceAll(funcName(opt), "[...]", "")
+	name = closureSuffix.ReplaceAllString(name, "")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,81 @@
+package options_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func withPort(port int) options.OptionE[server] {
+	return options.NamedE("WithPort", port, setPort(port))
+}
+
+func TestApplyEErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []options.OptionE[server]
+		want    server
+		wantErr []string
+	}{
+		{name: "valid", opts: []options.OptionE[server]{withPort(8080), options.E(withHost("a"))}, want: server{host: "a", port: 8080}},
+		{name: "named", opts: []options.OptionE[server]{withPort(0)}, wantErr: []string{"WithPort"}},
+		{name: "unnamed", opts: []options.OptionE[server]{setPort(0)}, wantErr: []string{"setPort"}},
+		{
+			name:    "every failure",
+			opts:    []options.OptionE[server]{withPort(0), options.E(withHost("a")), withPort(70000)},
+			want:    server{host: "a"},
+			wantErr: []string{"WithPort", "WithPort"},
+		},
+		{
+			name:    "nested",
+			opts:    []options.OptionE[server]{options.CombineE(withPort(0), setPort(0))},
+			wantErr: []string{"WithPort", "setPort"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(server)
+			err := options.ApplyE(got, tt.opts...)
+			if names := optionErrors(err); !reflect.DeepEqual(names, tt.wantErr) {
+				t.Fatalf("ApplyE() failed options = %v, want %v (error %v)", names, tt.wantErr, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, errPort) {
+				t.Errorf("ApplyE() error = %v, want it to wrap %v", err, errPort)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ApplyE() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEGenericName(t *testing.T) {
+	var timeout time.Duration
+	err := options.ApplyE(new(server), options.Duration(func(*server) *time.Duration { return &timeout }, "soon"))
+	if names, want := optionErrors(err), []string{"Duration"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ApplyE() failed options = %v, want %v (error %v)", names, want, err)
+	}
+}
+
+// optionErrors returns the names of the options whose errors are joined in
+// err.
+func optionErrors(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var names []string
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			names = append(names, optionErrors(err)...)
+		}
+		return names
+	}
+	var opt *options.OptionError
+	if errors.As(err, &opt) {
+		return []string{opt.Name}
+	}
+	return []string{err.Error()}
+}
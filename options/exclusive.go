@@ -0,0 +1,42 @@
+package options
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrConflictingOptions is returned by ApplyE when mutually exclusive
+// options were supplied together.
+type ErrConflictingOptions struct {
+	Names []string
+}
+
+func (e ErrConflictingOptions) Error() string {
+	return fmt.Sprintf("options %s are mutually exclusive", strings.Join(e.Names, ", "))
+}
+
+// Exclusive declares that at most one of the named options, see Named, may
+// be supplied in the same ApplyE call. The check runs after all other
+// options, so its position in the list does not matter.
+func Exclusive[T any](names ...string) OptionE[T] {
+	return func(t *T) error {
+		s := scopeOf(t)
+		if s == nil || s.probing {
+			return nil
+		}
+		s.policy()
+		s.checks = append(s.checks, func() error {
+			var supplied []string
+			for _, name := range names {
+				if s.seen[name] > 0 {
+					supplied = append(supplied, name)
+				}
+			}
+			if len(supplied) > 1 {
+				return ErrConflictingOptions{Names: supplied}
+			}
+			return nil
+		})
+		return nil
+	}
+}
@@ -0,0 +1,34 @@
+package options_test
+
+import (
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestExclusive(t *testing.T) {
+	exclusive := options.Exclusive[server]("WithHost", "WithTag")
+	tests := []struct {
+		name    string
+		opts    []options.OptionE[server]
+		wantErr string
+	}{
+		{name: "none", opts: []options.OptionE[server]{exclusive}},
+		{name: "one", opts: []options.OptionE[server]{exclusive, options.E(withHost("a"))}},
+		{name: "one repeated", opts: []options.OptionE[server]{options.E(withTag("x")), exclusive, options.E(withTag("y"))}},
+		{name: "unlisted", opts: []options.OptionE[server]{options.E(withTag("x")), exclusive, setPort(80)}},
+		{
+			name:    "two in declared order",
+			opts:    []options.OptionE[server]{options.E(withTag("x")), exclusive, options.E(withHost("a"))},
+			wantErr: "options WithHost, WithTag are mutually exclusive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := options.ApplyE(new(server), tt.opts...)
+			if got := errorString(err); got != tt.wantErr {
+				t.Errorf("ApplyE() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
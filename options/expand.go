@@ -0,0 +1,34 @@
+package options
+
+// Expander is implemented by values that stand for a list of options, so a
+// helper such as ProductionDefaults can return a single value that ApplyAll
+// expands in place.
+type Expander[T any] interface {
+	Expand() []Option[T]
+}
+
+// Expand returns the option itself, so every Option is an Expander.
+func (o Option[T]) Expand() []Option[T] {
+	return []Option[T]{o}
+}
+
+// Expand returns the options of the set.
+func (s OptionSet[T]) Expand() []Option[T] {
+	return s.Clone()
+}
+
+// Expand returns the default options.
+func (d Defaults[T]) Expand() []Option[T] {
+	return append([]Option[T](nil), d...)
+}
+
+// Flatten expands items into a single list of options.
+func Flatten[T any](items ...Expander[T]) []Option[T] {
+	var opts []Option[T]
+	for _, item := range items {
+		if item != nil {
+			opts = append(opts, item.Expand()...)
+		}
+	}
+	return opts
+}
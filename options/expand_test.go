@@ -0,0 +1,26 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestApplyAll(t *testing.T) {
+	defaults := options.NewSet(withHost("localhost"), withTag("default"))
+	got := options.ApplyAll[server](new(server), defaults, withTag("x"), nil, options.WithDefaults(withTag("y")))
+	if want := (server{host: "localhost", tags: []string{"default", "x", "y"}}); !reflect.DeepEqual(*got, want) {
+		t.Errorf("ApplyAll() = %+v, want %+v", *got, want)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	opts := options.Flatten[server](options.NewSet(withHost("a"), withTag("x")), withTag("y"), nil)
+	if len(opts) != 3 {
+		t.Fatalf("Flatten() returned %d options, want 3", len(opts))
+	}
+	if got, want := options.Apply(new(server), opts...), (server{host: "a", tags: []string{"x", "y"}}); !reflect.DeepEqual(*got, want) {
+		t.Errorf("Apply(Flatten()) = %+v, want %+v", *got, want)
+	}
+}
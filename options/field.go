@@ -0,0 +1,74 @@
+package options
+
+import "reflect"
+
+// Set assigns value to the field returned by get, which turns trivial
+// options into one-liners:
+//
+//	func WithLogger(logger ILogger) options.Option[Client] {
+//		return options.Set(func(c *Client) *ILogger { return &c.logger }, logger)
+//	}
+func Set[T, V any](get func(*T) *V, value V) Option[T] {
+	return func(t *T) {
+		*get(t) = value
+	}
+}
+
+// Reset sets the field returned by accessor back to its zero value, so
+// callers can undo a default without a dedicated option for every field.
+//
+//	options.Reset(func(c *Client) *map[string]string { return &c.header })
+func Reset[T, V any](accessor func(*T) *V) Option[T] {
+	return func(t *T) {
+		var zero V
+		*accessor(t) = zero
+	}
+}
+
+// AppendTo appends values to the slice returned by get instead of replacing
+// it.
+func AppendTo[T, E any](get func(*T) *[]E, values ...E) Option[T] {
+	return func(t *T) {
+		s := get(t)
+		*s = append(*s, values...)
+	}
+}
+
+// MergeMap copies the entries of m into the map returned by get, allocating
+// it when nil, so options such as WithHeader can add entries instead of
+// replacing the whole map:
+//
+//	func WithHeader(header map[string]string) options.Option[Client] {
+//		return options.MergeMap(func(c *Client) *map[string]string { return &c.header }, header)
+//	}
+func MergeMap[T any, K comparable, V any](get func(*T) *map[K]V, m map[K]V) Option[T] {
+	return func(t *T) {
+		dst := get(t)
+		if *dst == nil {
+			*dst = make(map[K]V, len(m))
+		}
+		for k, v := range m {
+			(*dst)[k] = v
+		}
+	}
+}
+
+// Decorate replaces the value of the field returned by get with wrap applied
+// to it, so options can wrap interface valued fields instead of replacing
+// them:
+//
+//	options.Decorate(func(c *Client) *ILogger { return &c.logger }, func(l ILogger) ILogger {
+//		return prefixLogger{prefix: "client: ", next: l}
+//	})
+func Decorate[T, I any](get func(*T) *I, wrap func(I) I) Option[T] {
+	return func(t *T) {
+		field := get(t)
+		*field = wrap(*field)
+	}
+}
+
+// IsZero reports whether v is the zero value of its type; generated code
+// uses it to skip unset fields.
+func IsZero[V any](v V) bool {
+	return reflect.ValueOf(&v).Elem().IsZero()
+}
@@ -0,0 +1,101 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestReset(t *testing.T) {
+	got := options.Apply(&server{host: "a", tags: []string{"x"}},
+		options.Reset(func(s *server) *[]string { return &s.tags }))
+	if got.host != "a" || got.tags != nil {
+		t.Errorf("Apply(Reset()) = %+v, want only the tags reset", *got)
+	}
+}
+
+func TestSet(t *testing.T) {
+	got := options.Apply(&server{host: "a", port: 80},
+		options.Set(func(s *server) *string { return &s.host }, "b"))
+	if want := (server{host: "b", port: 80}); !reflect.DeepEqual(*got, want) {
+		t.Errorf("Apply(Set()) = %+v, want %+v", *got, want)
+	}
+}
+
+func TestAppendTo(t *testing.T) {
+	tags := func(s *server) *[]string { return &s.tags }
+	got := options.Apply(&server{tags: []string{"x"}}, options.AppendTo(tags, "y", "z"), options.AppendTo(tags))
+	if want := []string{"x", "y", "z"}; !reflect.DeepEqual(got.tags, want) {
+		t.Errorf("tags = %q, want %q", got.tags, want)
+	}
+}
+
+func TestMergeMap(t *testing.T) {
+	type client struct {
+		header map[string]string
+	}
+	header := func(c *client) *map[string]string { return &c.header }
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   map[string]string
+	}{
+		{name: "nil", want: map[string]string{"a": "1", "b": "3"}},
+		{name: "merged", header: map[string]string{"a": "0", "c": "4"}, want: map[string]string{"a": "1", "b": "3", "c": "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := options.Apply(&client{header: tt.header},
+				options.MergeMap(header, map[string]string{"a": "1", "b": "2"}),
+				options.MergeMap(header, map[string]string{"b": "3"}))
+			if !reflect.DeepEqual(got.header, tt.want) {
+				t.Errorf("header = %v, want %v", got.header, tt.want)
+			}
+		})
+	}
+}
+
+type greeter interface {
+	greet() string
+}
+
+type greeting string
+
+func (g greeting) greet() string { return string(g) }
+
+type loud struct{ next greeter }
+
+func (l loud) greet() string { return l.next.greet() + "!" }
+
+func TestDecorate(t *testing.T) {
+	type client struct {
+		greeter greeter
+	}
+	louder := options.Decorate(func(c *client) *greeter { return &c.greeter }, func(g greeter) greeter { return loud{next: g} })
+	got := options.Apply(&client{greeter: greeting("hi")}, louder, louder)
+	if got := got.greeter.greet(); got != "hi!!" {
+		t.Errorf("greet() = %q, want %q", got, "hi!!")
+	}
+}
+
+func TestIsZero(t *testing.T) {
+	var nilMap map[string]string
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{name: "zero int", got: options.IsZero(0), want: true},
+		{name: "int", got: options.IsZero(1)},
+		{name: "nil map", got: options.IsZero(nilMap), want: true},
+		{name: "empty map", got: options.IsZero(map[string]string{})},
+		{name: "zero struct", got: options.IsZero(server{}), want: true},
+		{name: "nil interface", got: options.IsZero[greeter](nil), want: true},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("IsZero() for %s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
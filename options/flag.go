@@ -0,0 +1,34 @@
+package options
+
+// FlagProvider reports whether a feature flag is enabled, typically backed
+// by a flag service or a rollout configuration.
+type FlagProvider interface {
+	Enabled(name string) bool
+}
+
+// FlagFunc adapts a function to a FlagProvider.
+type FlagFunc func(name string) bool
+
+// Enabled calls f.
+func (f FlagFunc) Enabled(name string) bool {
+	return f(name)
+}
+
+// WhenFlag applies opt only when the feature flag name is enabled in
+// provider. The flag is checked every time the option is applied, so a
+// rollout changes how values are constructed without changing the code:
+//
+//	client := New(baseURL, options.WhenFlag(flags, "new-retry-policy", WithRetryV2()))
+func WhenFlag[T any](provider FlagProvider, name string, opt Option[T]) Option[T] {
+	return IfFunc(func(*T) bool { return provider.Enabled(name) }, opt)
+}
+
+// WhenFlagE is the fallible counterpart of WhenFlag.
+func WhenFlagE[T any](provider FlagProvider, name string, opt OptionE[T]) OptionE[T] {
+	return func(t *T) error {
+		if opt != nil && provider.Enabled(name) {
+			return opt(t)
+		}
+		return nil
+	}
+}
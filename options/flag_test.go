@@ -0,0 +1,35 @@
+package options_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestWhenFlag(t *testing.T) {
+	enabled := map[string]bool{"on": true}
+	flags := options.FlagFunc(func(name string) bool { return enabled[name] })
+
+	got := options.Apply(new(server), options.WhenFlag(flags, "on", setHost("a")), options.WhenFlag(flags, "off", addTag("x")))
+	if got.host != "a" || got.tags != nil {
+		t.Errorf("Apply(WhenFlag()) = %+v, want only the enabled option", *got)
+	}
+
+	// The flag is checked whenever the option is applied.
+	opt := options.WhenFlag(flags, "rollout", setHost("b"))
+	enabled["rollout"] = true
+	if got := options.Apply(new(server), opt); got.host != "b" {
+		t.Errorf("Apply(WhenFlag()) after enabling the flag: host = %q, want b", got.host)
+	}
+}
+
+func TestWhenFlagE(t *testing.T) {
+	flags := options.FlagFunc(func(name string) bool { return name == "on" })
+	if err := options.ApplyE(new(server), options.WhenFlagE(flags, "off", setPort(0)), options.WhenFlagE[server](flags, "on", nil)); err != nil {
+		t.Errorf("ApplyE(disabled WhenFlagE()) = %v, want nil", err)
+	}
+	if err := options.ApplyE(new(server), options.WhenFlagE(flags, "on", setPort(0))); !errors.Is(err, errPort) {
+		t.Errorf("ApplyE(enabled WhenFlagE()) = %v, want %v", err, errPort)
+	}
+}
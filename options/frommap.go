@@ -0,0 +1,86 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+// FromMap translates m into options for the struct type T, so dynamic input
+// such as parsed JSON can be turned into type checked options. Keys are
+// matched against the field keys of T, see the package documentation, first
+// exactly and then ignoring case. Values are converted to the field type,
+// strings are parsed for scalar fields and by the UnmarshalText method of
+// types implementing encoding.TextUnmarshaler. A map[string]any value for a field
+// holding a struct, or a pointer to one, sets the nested fields it has keys
+// for and keeps the others. Only keys present in m produce options; every
+// unknown key or unconvertible value is reported.
+func FromMap[T any](m map[string]any) ([]Option[T], error) {
+	assigns, errs := assignments(reflect.TypeFor[T](), m, "", nil)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	opts := make([]Option[T], len(assigns))
+	for i, a := range assigns {
+		opts[i] = setField[T](a)
+	}
+	return opts, nil
+}
+
+// assignment sets the field reached through index to value.
+type assignment struct {
+	// key is the dotted key of the field, such as retry.max_attempts.
+	key   string
+	value reflect.Value
+	index [][]int
+}
+
+// assignments converts the values of m for the fields of the struct type t.
+func assignments(t reflect.Type, m map[string]any, prefix string, index [][]int) ([]assignment, []error) {
+	available := fields.Fields(t)
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var (
+		out  []assignment
+		errs []error
+	)
+	for _, key := range keys {
+		f, ok := fields.Lookup(available, key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown key %q", prefix+key))
+			continue
+		}
+		fieldIndex := append(append([][]int(nil), index...), f.Index)
+		nested := f.Type
+		if nested.Kind() == reflect.Pointer {
+			nested = nested.Elem()
+		}
+		if sub, ok := m[key].(map[string]any); ok && nested.Kind() == reflect.Struct {
+			a, e := assignments(nested, sub, prefix+key+".", fieldIndex)
+			out, errs = append(out, a...), append(errs, e...)
+			continue
+		}
+		v, err := fields.Convert(m[key], f.Type)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("key %q: %w", prefix+key, err))
+			continue
+		}
+		out = append(out, assignment{key: prefix + key, value: v, index: fieldIndex})
+	}
+	return out, errs
+}
+
+// setField returns an option named after the field key that assigns the
+// value of a.
+func setField[T any](a assignment) Option[T] {
+	return Named(a.key, a.value.Interface(), func(t *T) {
+		fields.Set(reflect.ValueOf(t).Elem(), a.index, a.value)
+	})
+}
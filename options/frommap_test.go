@@ -0,0 +1,49 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type config struct {
+	BaseURL string
+	Timeout time.Duration `option:"key=request_timeout"`
+	Retries uint8
+	Tags    []string
+}
+
+func TestFromMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       map[string]any
+		want    config
+		wantErr string
+	}{
+		{name: "empty", m: map[string]any{}},
+		{
+			name: "converted",
+			m:    map[string]any{"base_url": "http://a", "request_timeout": "5s", "retries": 3.0, "tags": "x, y"},
+			want: config{BaseURL: "http://a", Timeout: 5 * time.Second, Retries: 3, Tags: []string{"x", "y"}},
+		},
+		{name: "case insensitive", m: map[string]any{"Base_URL": "http://a"}, want: config{BaseURL: "http://a"}},
+		{
+			name:    "every error",
+			m:       map[string]any{"base_url": 1, "timeout": "5s", "retries": "x"},
+			wantErr: "key \"base_url\": cannot use int as string\nkey \"retries\": strconv.ParseUint: parsing \"x\": invalid syntax\nunknown key \"timeout\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := options.FromMap[config](tt.m)
+			if got := errorString(err); got != tt.wantErr {
+				t.Fatalf("FromMap() error = %q, want %q", got, tt.wantErr)
+			}
+			if got := options.Apply(new(config), opts...); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Apply(FromMap()) = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package options
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+// FromStruct translates the non-zero fields of seed into options for the
+// struct type T, so callers can keep filling in a config struct while the
+// constructor gets options that only touch what was set:
+//
+//	opts, err := options.FromStruct[Client](Config{BaseURL: "https://api.example.com"})
+//	client, err := New(append(opts, WithTimeout(time.Second))...)
+//
+// Fields of seed, which may also be a pointer to a struct or a T itself,
+// are matched against the field keys of T like the keys of FromMap, and
+// their values are converted the same way. Non-zero nested structs declared
+// in the package of seed set the nested fields they have non-zero values
+// for and keep the others. Every field without a counterpart in T or with
+// an unconvertible value is reported.
+func FromStruct[T, S any](seed S) ([]Option[T], error) {
+	v := reflect.ValueOf(&seed).Elem()
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("options: %s is not a struct", v.Type())
+	}
+	return FromMap[T](nonZero(v, v.Type().PkgPath()))
+}
+
+// nonZero returns the non-zero fields of the struct v by field key,
+// descending into the nested structs declared in the package pkg.
+func nonZero(v reflect.Value, pkg string) map[string]any {
+	m := map[string]any{}
+	for _, f := range fields.Fields(v.Type()) {
+		fv := fields.Access(v.FieldByIndex(f.Index))
+		if fv.IsZero() {
+			continue
+		}
+		nested := fv
+		if nested.Kind() == reflect.Pointer {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && nested.Type().PkgPath() == pkg && !fields.TextUnmarshaler(f.Type) {
+			if sub := nonZero(nested, pkg); len(sub) > 0 {
+				m[f.Key] = sub
+			}
+			continue
+		}
+		m[f.Key] = fv.Interface()
+	}
+	return m
+}
@@ -0,0 +1,58 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type seed struct {
+	BaseURL        string
+	RequestTimeout time.Duration
+	Retries        int
+}
+
+func TestFromStruct(t *testing.T) {
+	defaults := config{Timeout: time.Second, Retries: 1}
+	apply := func(t *testing.T, opts []options.Option[config], err error, want config) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("FromStruct() error = %v", err)
+		}
+		got := defaults
+		if options.Apply(&got, opts...); !reflect.DeepEqual(got, want) {
+			t.Errorf("Apply(FromStruct()) = %+v, want %+v", got, want)
+		}
+	}
+
+	t.Run("zero", func(t *testing.T) {
+		opts, err := options.FromStruct[config](seed{})
+		apply(t, opts, err, defaults)
+	})
+	t.Run("nil pointer", func(t *testing.T) {
+		opts, err := options.FromStruct[config]((*seed)(nil))
+		apply(t, opts, err, defaults)
+	})
+	t.Run("non-zero fields", func(t *testing.T) {
+		opts, err := options.FromStruct[config](&seed{BaseURL: "http://a", RequestTimeout: 5 * time.Second, Retries: 3})
+		apply(t, opts, err, config{BaseURL: "http://a", Timeout: 5 * time.Second, Retries: 3})
+	})
+	t.Run("target type", func(t *testing.T) {
+		opts, err := options.FromStruct[config](config{Tags: []string{"x"}})
+		apply(t, opts, err, config{Timeout: time.Second, Retries: 1, Tags: []string{"x"}})
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := options.FromStruct[config](struct{ Proxy string }{"p"})
+		if got, want := errorString(err), `unknown key "proxy"`; got != want {
+			t.Errorf("FromStruct() error = %q, want %q", got, want)
+		}
+	})
+	t.Run("not a struct", func(t *testing.T) {
+		_, err := options.FromStruct[config](1)
+		if got, want := errorString(err), "options: int is not a struct"; got != want {
+			t.Errorf("FromStruct() error = %q, want %q", got, want)
+		}
+	})
+}
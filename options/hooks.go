@@ -0,0 +1,32 @@
+package options
+
+import "context"
+
+// BeforeApplier is implemented by targets that seed their state before any
+// option runs, for example allocating maps that options add entries to.
+// BeforeApply is called at the start of an apply call, except for the calls
+// configuring a copy or an existing value, such as CloneWith and
+// Reconfigure.
+type BeforeApplier interface {
+	BeforeApply()
+}
+
+// AfterApplier is implemented by targets that derive state from their
+// configuration, such as compiling patterns or building an http.Client from
+// accumulated transport settings. AfterApply is called once all options of
+// an apply call have run.
+type AfterApplier interface {
+	AfterApply(ctx context.Context)
+}
+
+func afterApply(ctx context.Context, target any) {
+	if a, ok := target.(AfterApplier); ok {
+		a.AfterApply(ctx)
+	}
+}
+
+func beforeApply(target any) {
+	if b, ok := target.(BeforeApplier); ok {
+		b.BeforeApply()
+	}
+}
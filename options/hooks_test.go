@@ -0,0 +1,89 @@
+package options_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// hooked records the hooks and options run against it.
+type hooked struct {
+	events []string
+}
+
+func (h *hooked) BeforeApply()               { h.events = append(h.events, "before") }
+func (h *hooked) AfterApply(context.Context) { h.events = append(h.events, "after") }
+
+func event(name string) options.Option[hooked] {
+	return func(h *hooked) { h.events = append(h.events, name) }
+}
+
+func TestHooks(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(h *hooked) *hooked
+		want  []string
+	}{
+		{
+			name:  "Apply",
+			apply: func(h *hooked) *hooked { return options.Apply(h, event("a"), event("b")) },
+			want:  []string{"before", "a", "b", "after"},
+		},
+		{
+			name: "ApplyE",
+			apply: func(h *hooked) *hooked {
+				_ = options.ApplyE(h, options.E(event("a")))
+				return h
+			},
+			want: []string{"before", "a", "after"},
+		},
+		{
+			name: "nested",
+			apply: func(h *hooked) *hooked {
+				return options.Apply(h, options.Option[hooked](func(h *hooked) { options.Apply(h, event("inner")) }))
+			},
+			want: []string{"before", "inner", "after"},
+		},
+		{
+			name: "Defaults",
+			apply: func(h *hooked) *hooked {
+				return options.WithDefaults(event("default")).Apply(h, event("a"))
+			},
+			want: []string{"before", "default", "a", "after"},
+		},
+		{
+			name: "Layers",
+			apply: func(h *hooked) *hooked {
+				options.Layers[hooked]{
+					options.NewLayer("defaults", event("default")),
+					options.NewLayer("caller", event("a")),
+				}.Apply(h)
+				return h
+			},
+			want: []string{"before", "default", "a", "after"},
+		},
+		{
+			name:  "CloneWith",
+			apply: func(h *hooked) *hooked { return options.CloneWith(h, event("a")) },
+			want:  []string{"a", "after"},
+		},
+		{
+			name: "Reconfigure",
+			apply: func(h *hooked) *hooked {
+				_ = options.Reconfigure(h, event("a"))
+				return h
+			},
+			want: []string{"a", "after"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.apply(new(hooked))
+			if !reflect.DeepEqual(got.events, tt.want) {
+				t.Errorf("events = %v, want %v", got.events, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package options
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+// Layer is a named group of options from one source, such as hardcoded
+// defaults, the environment, a config file or explicit caller options.
+type Layer[T any] struct {
+	Name    string
+	Options []Option[T]
+}
+
+// NewLayer creates a layer called name.
+func NewLayer[T any](name string, opts ...Option[T]) Layer[T] {
+	return Layer[T]{Name: name, Options: opts}
+}
+
+// Layers applies layers in the declared order, so later layers take
+// precedence over earlier ones.
+type Layers[T any] []Layer[T]
+
+// Provenance maps the dotted path of every field configured by a layer to
+// the name of the last layer that changed it.
+type Provenance map[string]string
+
+// Apply runs the layers against target and reports which layer configured
+// which field. T must be a struct. The BeforeApply and AfterApply hooks run
+// once around all layers, so each layer builds on the state of the earlier
+// ones.
+//
+// Options are opaque functions, so the provenance is derived from the values
+// each layer leaves behind rather than from its writes: a layer assigning a
+// field the value it already holds, such as a config file repeating a
+// default, leaves the field credited to the earlier layer.
+func (l Layers[T]) Apply(target *T) Provenance {
+	beforeApply(target)
+	provenance := Provenance{}
+	for _, layer := range l {
+		before := fields.Copy(target)
+		apply(target, false, false, layer.Options)
+		for _, d := range fields.Diff(reflect.ValueOf(before).Elem(), reflect.ValueOf(target).Elem()) {
+			provenance[d.Path] = layer.Name
+		}
+	}
+	afterApply(context.Background(), target)
+	return provenance
+}
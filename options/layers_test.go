@@ -0,0 +1,53 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func withMaxConns(n int) options.Option[server] {
+	return func(s *server) { s.limits.maxConns = n }
+}
+
+func TestLayersApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		layers options.Layers[server]
+		want   server
+		wantP  options.Provenance
+	}{
+		{name: "no layers", want: server{}, wantP: options.Provenance{}},
+		{
+			name: "later layers win",
+			layers: options.Layers[server]{
+				options.NewLayer("defaults", withHost("localhost"), withMaxConns(10)),
+				options.NewLayer("env", withHost("example.com")),
+			},
+			want:  server{host: "example.com", limits: limits{maxConns: 10}},
+			wantP: options.Provenance{"host": "env", "limits.maxConns": "defaults"},
+		},
+		{
+			name: "repeated values keep the earlier layer",
+			layers: options.Layers[server]{
+				options.NewLayer("defaults", withHost("localhost")),
+				options.NewLayer("file", withHost("localhost"), withTag("x")),
+			},
+			want:  server{host: "localhost", tags: []string{"x"}},
+			wantP: options.Provenance{"host": "defaults", "tags": "file"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(server)
+			p := tt.layers.Apply(got)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Apply() target = %+v, want %+v", *got, tt.want)
+			}
+			if !reflect.DeepEqual(p, tt.wantP) {
+				t.Errorf("Apply() = %v, want %v", p, tt.wantP)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package options
+
+// Lazy defers building an option until it is applied, so expensive values
+// such as certificates read from disk are only computed when needed. build
+// is called on every application and is skipped by Describe.
+func Lazy[T any](build func() Option[T]) Option[T] {
+	return func(t *T) {
+		if s := scopeOf(t); s != nil && s.probing {
+			return
+		}
+		if opt := build(); opt != nil {
+			opt(t)
+		}
+	}
+}
+
+// LazyE is the fallible counterpart of Lazy.
+func LazyE[T any](build func() OptionE[T]) OptionE[T] {
+	return func(t *T) error {
+		if s := scopeOf(t); s != nil && s.probing {
+			return nil
+		}
+		if opt := build(); opt != nil {
+			return opt(t)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,35 @@
+package options_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestLazy(t *testing.T) {
+	var builds int
+	lazy := options.Lazy(func() options.Option[server] {
+		builds++
+		return withHost("a")
+	})
+	options.Describe(lazy)
+	if builds != 0 {
+		t.Fatalf("Describe() built the option %d times", builds)
+	}
+	for i := 1; i <= 2; i++ {
+		if got := options.Apply(new(server), lazy); got.host != "a" {
+			t.Errorf("host = %q, want %q", got.host, "a")
+		}
+		if builds != i {
+			t.Errorf("built %d times, want %d", builds, i)
+		}
+	}
+}
+
+func TestLazyE(t *testing.T) {
+	lazy := options.LazyE(func() options.OptionE[server] { return setPort(0) })
+	if err := options.ApplyE(new(server), lazy); !errors.Is(err, errPort) {
+		t.Errorf("ApplyE() error = %v, want %v", err, errPort)
+	}
+}
@@ -0,0 +1,46 @@
+package options
+
+// Lift turns an option for B into an option for A by applying it to the B
+// returned by get, so options written for a sub-config can be reused on the
+// type embedding it:
+//
+//	options.Lift(func(c *Client) *http.Client { return c.baseClient }, WithTimeout(5*time.Second))
+//
+// The option does nothing when get returns nil.
+func Lift[A, B any](get func(*A) *B, opt Option[B]) Option[A] {
+	return func(a *A) {
+		if b := get(a); b != nil {
+			Apply(b, opt)
+		}
+	}
+}
+
+// Sub applies subOpts to the sub-component returned by get, so a parent can
+// accept nested option trees for its components:
+//
+//	func WithTransport(opts ...options.Option[Transport]) options.Option[Client] {
+//		return options.Sub(func(c *Client) *Transport { return &c.transport }, opts...)
+//	}
+//
+//	client := New(baseURL, WithTransport(WithTimeout(5*time.Second)))
+//
+// The option does nothing when get returns nil; a get function for a pointer
+// field can allocate the component on first use instead.
+func Sub[T, S any](get func(*T) *S, subOpts ...Option[S]) Option[T] {
+	return func(t *T) {
+		if s := get(t); s != nil {
+			Apply(s, subOpts...)
+		}
+	}
+}
+
+// SubE is Sub for options that can fail. The errors of subOpts are returned
+// as reported by ApplyE.
+func SubE[T, S any](get func(*T) *S, subOpts ...OptionE[S]) OptionE[T] {
+	return func(t *T) error {
+		if s := get(t); s != nil {
+			return ApplyE(s, subOpts...)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package options_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type proxy struct {
+	upstream *server
+}
+
+func TestLift(t *testing.T) {
+	upstream := func(p *proxy) *server { return p.upstream }
+	got := options.Apply(&proxy{upstream: new(server)}, options.Lift(upstream, withHost("a")))
+	if got.upstream.host != "a" {
+		t.Errorf("upstream host = %q, want %q", got.upstream.host, "a")
+	}
+	if got := options.Apply(new(proxy), options.Lift(upstream, withHost("a"))); got.upstream != nil {
+		t.Errorf("upstream = %+v, want nil", got.upstream)
+	}
+}
+
+func TestSub(t *testing.T) {
+	upstream := func(p *proxy) *server {
+		if p.upstream == nil {
+			p.upstream = new(server)
+		}
+		return p.upstream
+	}
+	got := options.Apply(new(proxy), options.Sub(upstream, withHost("a"), withTag("x")), options.Sub(upstream, withTag("y")))
+	if want := (server{host: "a", tags: []string{"x", "y"}}); !reflect.DeepEqual(*got.upstream, want) {
+		t.Errorf("upstream = %+v, want %+v", *got.upstream, want)
+	}
+	if got := options.Apply(new(proxy), options.Sub(func(p *proxy) *server { return p.upstream }, withHost("a"))); got.upstream != nil {
+		t.Errorf("upstream = %+v, want nil", got.upstream)
+	}
+}
+
+func TestSubE(t *testing.T) {
+	upstream := func(p *proxy) *server {
+		if p.upstream == nil {
+			p.upstream = new(server)
+		}
+		return p.upstream
+	}
+	tests := []struct {
+		name    string
+		opt     options.OptionE[proxy]
+		wantErr string
+	}{
+		{name: "valid", opt: options.SubE(upstream, withPort(80), options.E(withHost("a")))},
+		{
+			name:    "named by the parent",
+			opt:     options.NamedE("WithUpstream", nil, options.SubE(upstream, withPort(0))),
+			wantErr: "option WithUpstream: option WithPort: port out of range",
+		},
+		{
+			name:    "same name once",
+			opt:     options.NamedE("WithPort", 0, options.SubE(upstream, withPort(0))),
+			wantErr: "option WithPort: port out of range",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := options.ApplyE(new(proxy), tt.opt, options.E(options.Lift(upstream, withTag("x"))))
+			if got := errorString(err); got != tt.wantErr {
+				t.Errorf("ApplyE() error = %q, want %q", got, tt.wantErr)
+			}
+			if tt.wantErr != "" && !errors.Is(err, errPort) {
+				t.Errorf("ApplyE() error = %v, want it to wrap %v", err, errPort)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package options
+
+import "fmt"
+
+// ErrTooManyOptions is returned by ApplyE when more options were supplied
+// than allowed by MaxOptions.
+type ErrTooManyOptions struct {
+	Max, Got int
+}
+
+func (e ErrTooManyOptions) Error() string {
+	return fmt.Sprintf("%d options supplied, at most %d allowed", e.Got, e.Max)
+}
+
+// MaxOptions limits the number of options a single ApplyE or ApplyCtx call
+// accepts to n, as a guardrail against unbounded option lists generated
+// from user input. Declarations such as Required, Exclusive and MaxOptions
+// itself do not count towards the limit.
+func MaxOptions[T any](n int) OptionE[T] {
+	return func(t *T) error {
+		s := scopeOf(t)
+		if s == nil || s.probing {
+			return nil
+		}
+		s.policy()
+		s.checks = append(s.checks, func() error {
+			if got := s.supplied - s.policies; got > n {
+				return ErrTooManyOptions{Max: n, Got: got}
+			}
+			return nil
+		})
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+package options_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestMaxOptions(t *testing.T) {
+	hostSet := options.Required("WithHost", func(s *server) bool { return s.host != "" })
+	tests := []struct {
+		name    string
+		opts    []options.OptionE[server]
+		wantErr error
+	}{
+		{name: "within", opts: []options.OptionE[server]{options.MaxOptions[server](2), options.E(withHost("a")), setPort(80)}},
+		{name: "declarations not counted", opts: []options.OptionE[server]{options.MaxOptions[server](1), hostSet, options.E(withHost("a"))}},
+		{
+			name:    "exceeded",
+			opts:    []options.OptionE[server]{options.E(withHost("a")), setPort(80), options.E(withTag("x")), options.MaxOptions[server](2)},
+			wantErr: options.ErrTooManyOptions{Max: 2, Got: 3},
+		},
+		{
+			name: "nested options not counted",
+			opts: []options.OptionE[server]{options.MaxOptions[server](1), options.E(options.Combine(withHost("a"), withTag("x")))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := options.ApplyE(new(server), tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ApplyE() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
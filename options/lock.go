@@ -0,0 +1,28 @@
+package options
+
+import "sync"
+
+// Locked bundles opts into a single option that holds mu while applying
+// them, so options touching shared state do not race with its readers.
+func Locked[T any](mu sync.Locker, opts ...Option[T]) Option[T] {
+	return func(t *T) {
+		if s := scopeOf(t); s == nil || !s.probing {
+			mu.Lock()
+			defer mu.Unlock()
+		}
+		Apply(t, opts...)
+	}
+}
+
+// SafeReconfigure is like Reconfigure but holds the lock of the target for
+// the whole call, so a shared value such as a client embedding a sync.Mutex
+// can be reconfigured at runtime while other goroutines use it under the
+// same lock.
+func SafeReconfigure[T any, PT interface {
+	*T
+	sync.Locker
+}](target PT, opts ...Option[T]) error {
+	target.Lock()
+	defer target.Unlock()
+	return Reconfigure((*T)(target), opts...)
+}
@@ -0,0 +1,50 @@
+package options_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type shared struct {
+	sync.Mutex
+	hits int
+}
+
+func hit(s *shared) { s.hits++ }
+
+func TestLocked(t *testing.T) {
+	var mu sync.Mutex
+	s := new(shared)
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			options.Apply(s, options.Locked(&mu, hit, hit))
+		}()
+	}
+	wg.Wait()
+	if s.hits != 100 {
+		t.Errorf("hits = %d, want 100", s.hits)
+	}
+}
+
+func TestSafeReconfigure(t *testing.T) {
+	s := new(shared)
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := options.SafeReconfigure(s, hit); err != nil {
+				t.Errorf("SafeReconfigure() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if !s.TryLock() || s.hits != 50 {
+		t.Errorf("hits = %d, want 50 and the lock released", s.hits)
+	}
+}
@@ -0,0 +1,61 @@
+package options
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// recorded is the JSON form of a named option.
+type recorded struct {
+	Name  string            `json:"name"`
+	Value any               `json:"value"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+// MarshalJSON encodes the named options contained in the set, see Describe,
+// as an array of name, value and tags in the order they are applied, so
+// the configuration of a value can be persisted and replayed later with
+// UnmarshalJSON. The name is the one the constructor of the option is
+// registered under, see Register, so UnmarshalJSON finds it again. Options
+// not created with Named cannot be replayed and fail the encoding.
+func (s OptionSet[T]) MarshalJSON() ([]byte, error) {
+	out := []recorded{}
+	for _, opt := range s {
+		if opt == nil {
+			continue
+		}
+		found, _ := probe(opt)
+		if len(found) == 0 {
+			return nil, fmt.Errorf("options: cannot marshal %s, it is not named", optionName(opt))
+		}
+		for _, d := range found {
+			n := d.(NamedOption[T])
+			out = append(out, recorded{Name: registeredName[T](n.Name()), Value: n.Value(), Tags: n.Tags()})
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON replaces the set with the options encoded by MarshalJSON.
+// Each option is rebuilt by the constructor registered for T under its
+// name, see Register, from the decoded value, and gets its tags attached
+// again. Unknown names and unconvertible values fail the decoding.
+func (s *OptionSet[T]) UnmarshalJSON(data []byte) error {
+	var in []recorded
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	set := make(OptionSet[T], 0, len(in))
+	for _, r := range in {
+		opt, err := Lookup[T](r.Name, r.Value)
+		if err != nil {
+			return fmt.Errorf("options: %w", err)
+		}
+		for key, value := range r.Tags {
+			opt = Tag(opt, key, value)
+		}
+		set = append(set, opt)
+	}
+	*s = set
+	return nil
+}
@@ -0,0 +1,100 @@
+package options_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type persisted struct {
+	host    string
+	timeout time.Duration
+}
+
+func withPersistedHost(host string) options.Option[persisted] {
+	return options.Named("WithHost", host, func(p *persisted) { p.host = host })
+}
+
+func withPersistedTimeout(d time.Duration) options.Option[persisted] {
+	return options.Named("WithTimeout", d, func(p *persisted) { p.timeout = d })
+}
+
+type aliased struct {
+	host string
+}
+
+func withAliasedHost(host string) options.Option[aliased] {
+	return options.Named("WithHost", host, func(a *aliased) { a.host = host })
+}
+
+func init() {
+	options.Register("WithHost", withPersistedHost)
+	options.Register("WithTimeout", withPersistedTimeout)
+	options.Register("host", withAliasedHost)
+}
+
+func TestMarshalJSON(t *testing.T) {
+	set := options.OptionSet[persisted]{
+		options.Tag(withPersistedHost("example.com"), "source", "file"),
+		nil,
+		withPersistedTimeout(5 * time.Second),
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"name":"WithHost","value":"example.com","tags":{"source":"file"}},{"name":"WithTimeout","value":5000000000}]`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var replayed options.OptionSet[persisted]
+	if err := json.Unmarshal(data, &replayed); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := options.Apply(new(persisted), replayed...), options.Apply(new(persisted), set...); *got != *want {
+		t.Errorf("Apply(Unmarshal(Marshal())) = %+v, want %+v", *got, *want)
+	}
+	if got := options.Tags(replayed[0]); !reflect.DeepEqual(got, map[string]string{"source": "file"}) {
+		t.Errorf("Tags(replayed) = %v, want the source tag", got)
+	}
+}
+
+func TestMarshalJSONRegisteredName(t *testing.T) {
+	data, err := json.Marshal(options.OptionSet[aliased]{withAliasedHost("example.com")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"name":"host","value":"example.com"}]`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var replayed options.OptionSet[aliased]
+	if err := json.Unmarshal(data, &replayed); err != nil {
+		t.Fatal(err)
+	}
+	if got := options.Apply(new(aliased), replayed...); got.host != "example.com" {
+		t.Errorf("Apply(Unmarshal(Marshal())) = %+v, want host example.com", *got)
+	}
+}
+
+func TestMarshalJSONErrors(t *testing.T) {
+	unnamed := options.OptionSet[persisted]{func(p *persisted) {}}
+	if _, err := json.Marshal(unnamed); err == nil || !strings.Contains(err.Error(), "it is not named") {
+		t.Errorf("Marshal(unnamed) error = %v, want it is not named", err)
+	}
+
+	var set options.OptionSet[persisted]
+	for data, want := range map[string]string{
+		`[{"name":"WithPort","value":1}]`:       `options: unknown option "WithPort"`,
+		`[{"name":"WithTimeout","value":true}]`: `options: option "WithTimeout": cannot use bool as time.Duration`,
+	} {
+		if err := json.Unmarshal([]byte(data), &set); err == nil || err.Error() != want {
+			t.Errorf("Unmarshal(%s) error = %v, want %q", data, err, want)
+		}
+	}
+}
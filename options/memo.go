@@ -0,0 +1,51 @@
+package options
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+// Memo runs opt once against a zero value of the struct type T and replays
+// the result on every application, so an expensive option such as parsing a
+// PEM bundle can be shared by many constructor calls. Only the fields opt
+// changes are assigned to the target, down to the fields of nested structs,
+// so their siblings keep the values of the target. Every application gets a
+// deep copy of the result as made by CloneWith. The error of the first run is
+// returned by every application.
+//
+// opt must not depend on the state of the target it is applied to.
+func Memo[T any](opt OptionE[T]) OptionE[T] {
+	var (
+		once   sync.Once
+		result *T
+		paths  [][]string
+		err    error
+	)
+	return func(t *T) error {
+		once.Do(func() {
+			result = new(T)
+			if err = opt(result); err != nil {
+				return
+			}
+			zero := reflect.ValueOf(new(T)).Elem()
+			for _, d := range fields.Diff(zero, reflect.ValueOf(result).Elem()) {
+				paths = append(paths, strings.Split(d.Path, "."))
+			}
+		})
+		if err != nil {
+			return err
+		}
+		src, dst := reflect.ValueOf(fields.Copy(result)).Elem(), reflect.ValueOf(t).Elem()
+		for _, path := range paths {
+			s, d := src, dst
+			for _, name := range path {
+				s, d = fields.Access(s.FieldByName(name)), fields.Access(d.FieldByName(name))
+			}
+			d.Set(s)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package options_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestMemo(t *testing.T) {
+	var runs int
+	memo := options.Memo(func(s *server) error {
+		runs++
+		s.host = "a"
+		s.limits.maxConns = 10
+		return nil
+	})
+	for range 2 {
+		got := new(server)
+		if err := options.ApplyE(got, options.E(setHost("b")), setPort(80), memo); err != nil {
+			t.Fatalf("ApplyE() error = %v", err)
+		}
+		if want := (server{host: "a", port: 80, limits: limits{maxConns: 10}}); !reflect.DeepEqual(*got, want) {
+			t.Errorf("ApplyE() = %+v, want %+v", *got, want)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("Memo() ran the option %d times, want once", runs)
+	}
+}
+
+func TestMemoError(t *testing.T) {
+	var runs int
+	memo := options.Memo(func(*server) error {
+		runs++
+		return errPort
+	})
+	for range 2 {
+		if err := options.ApplyE(new(server), memo); !errors.Is(err, errPort) {
+			t.Errorf("ApplyE() error = %v, want %v", err, errPort)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("Memo() ran the option %d times, want once", runs)
+	}
+}
+
+type bundle struct {
+	tls tlsFiles
+}
+
+type tlsFiles struct {
+	certs  []string
+	roots  map[string]string
+	verify bool
+}
+
+func TestMemoNested(t *testing.T) {
+	memo := options.Memo(func(b *bundle) error {
+		b.tls.certs = []string{"a.pem"}
+		b.tls.roots = map[string]string{"ca": "ca.pem"}
+		return nil
+	})
+	first := &bundle{tls: tlsFiles{verify: true}}
+	if err := options.ApplyE(first, memo); err != nil {
+		t.Fatalf("ApplyE() error = %v", err)
+	}
+	want := bundle{tls: tlsFiles{certs: []string{"a.pem"}, roots: map[string]string{"ca": "ca.pem"}, verify: true}}
+	if !reflect.DeepEqual(*first, want) {
+		t.Errorf("ApplyE() = %+v, want %+v", *first, want)
+	}
+
+	first.tls.certs[0] = "b.pem"
+	first.tls.roots["ca"] = "other.pem"
+	second := new(bundle)
+	if err := options.ApplyE(second, memo); err != nil {
+		t.Fatalf("ApplyE() error = %v", err)
+	}
+	want.tls.verify = false
+	if !reflect.DeepEqual(*second, want) {
+		t.Errorf("ApplyE() after changing the first target = %+v, want %+v", *second, want)
+	}
+}
@@ -0,0 +1,21 @@
+package options
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MustApply is like ApplyE but panics when an option fails. It is intended
+// for tests and package level variables where handling the error is noise.
+func MustApply[T any](target *T, opts ...OptionE[T]) *T {
+	if err := ApplyE(target, opts...); err != nil {
+		panic(fmt.Errorf("options: configuring %s: %w", reflect.TypeFor[T](), err))
+	}
+	return target
+}
+
+// MustNew applies opts to a new zero value of T and panics when an option
+// fails, see MustApply.
+func MustNew[T any](opts ...OptionE[T]) *T {
+	return MustApply(new(T), opts...)
+}
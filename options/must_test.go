@@ -0,0 +1,24 @@
+package options_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestMustNew(t *testing.T) {
+	if got := options.MustNew(options.E(setHost("a")), setPort(80)); got.host != "a" || got.port != 80 {
+		t.Errorf("MustNew() = %+v, want host a and port 80", *got)
+	}
+}
+
+func TestMustApplyPanics(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, errPort) {
+			t.Errorf("MustApply() panicked with %v, want %v", err, errPort)
+		}
+	}()
+	options.MustApply(new(server), setPort(0))
+}
@@ -0,0 +1,139 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"runtime"
+)
+
+// NamedOption describes an option by the name it was declared with, the
+// value it was constructed from and the metadata attached with Tag.
+type NamedOption[T any] interface {
+	Name() string
+	Value() any
+	Option() Option[T]
+	Tags() map[string]string
+}
+
+type namedOption[T any] struct {
+	name  string
+	value any
+	opt   Option[T]
+	tags  map[string]string
+}
+
+func (n *namedOption[T]) Name() string            { return n.name }
+func (n *namedOption[T]) Value() any              { return n.value }
+func (n *namedOption[T]) Option() Option[T]       { return n.opt }
+func (n *namedOption[T]) Tags() map[string]string { return maps.Clone(n.tags) }
+
+// withTag returns a copy of n carrying the additional tag.
+func (n *namedOption[T]) withTag(key, value string) *namedOption[T] {
+	tagged := *n
+	tagged.tags = maps.Clone(n.tags)
+	if tagged.tags == nil {
+		tagged.tags = map[string]string{}
+	}
+	tagged.tags[key] = value
+	return &tagged
+}
+
+func (n *namedOption[T]) String() string {
+	if n.value == nil {
+		return n.name
+	}
+	return fmt.Sprintf("%s(%v)", n.name, n.value)
+}
+
+// Named attaches a name and the value it was built from to opt, so the
+// option can be reported by Describe.
+func Named[T any](name string, value any, opt Option[T]) Option[T] {
+	opt = NotNil(opt)
+	n := &namedOption[T]{name: name, value: value, opt: opt}
+	return func(t *T) {
+		if s := scopeOf(t); s != nil {
+			if s.probing {
+				s.described = append(s.described, n)
+				return
+			}
+			s.record(name)
+		}
+		opt(t)
+	}
+}
+
+// NamedE is the fallible counterpart of Named. Errors returned by opt are
+// reported by ApplyE under name.
+func NamedE[T any](name string, value any, opt OptionE[T]) OptionE[T] {
+	return func(t *T) error {
+		if s := scopeOf(t); s != nil && !s.probing {
+			s.record(name)
+		}
+		if err := opt(t); err != nil {
+			// Errors of nested options, such as those applied through SubE,
+			// may already carry the name.
+			var named *OptionError
+			if errors.As(err, &named) && named.Name == name {
+				return err
+			}
+			return &OptionError{Name: name, Err: err}
+		}
+		return nil
+	}
+}
+
+// Describe reports the named options contained in opts, looking through
+// options that bundle others such as Combine. Options that were not created
+// with Named are reported by the name of their function and a nil value.
+//
+// Describe invokes each option against a scratch zero value of T. Named
+// options and the wrappers of this package that guard them, such as Once
+// and Deprecated, only record themselves while being described, and Lazy
+// skips building its option. Every other option runs its configuration
+// against the scratch value, including the field helpers such as Set,
+// AppendTo and Decorate. Sub and Lift apply their options to the
+// sub-component in a scope of its own, so the named options passed to them
+// are run rather than reported.
+func Describe[T any](opts ...Option[T]) []NamedOption[T] {
+	var described []NamedOption[T]
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		found, tags := probe(opt)
+		if len(found) == 0 {
+			described = append(described, &namedOption[T]{name: funcName(opt), opt: opt, tags: tags})
+			continue
+		}
+		for _, d := range found {
+			described = append(described, d.(NamedOption[T]))
+		}
+	}
+	return described
+}
+
+// probe applies opt to a scratch target in probing mode and returns the
+// named options and tags the option recorded about itself.
+func probe[T any](opt Option[T]) (found []any, tags map[string]string) {
+	scratch := new(T)
+	s, _ := begin(scratch)
+	s.probing = true
+	defer func() {
+		end(scratch, s)
+		// Plain options run against the zero value and may panic on it,
+		// which must not break the description of the others.
+		_ = recover()
+		found, tags = s.described, s.tags
+	}()
+	opt(scratch)
+	return s.described, s.tags
+}
+
+func funcName(fn any) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package options_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func withHost(host string) options.Option[server] {
+	return options.Named("WithHost", host, setHost(host))
+}
+
+func withTag(tag string) options.Option[server] {
+	return options.Named("WithTag", tag, addTag(tag))
+}
+
+func TestNamed(t *testing.T) {
+	got := options.Apply(new(server), withHost("a"), withTag("x"))
+	want := server{host: "a", tags: []string{"x"}}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("Apply() = %+v, want %+v", *got, want)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	var runs int
+	counted := options.Named("Counted", nil, func(*server) { runs++ })
+	tests := []struct {
+		name string
+		opts []options.Option[server]
+		want []string
+	}{
+		{name: "named", opts: []options.Option[server]{withHost("a"), counted}, want: []string{"WithHost(a)", "Counted"}},
+		{name: "combined", opts: []options.Option[server]{options.Combine(withTag("x"), withTag("y"))}, want: []string{"WithTag(x)", "WithTag(y)"}},
+		{name: "unnamed", opts: []options.Option[server]{setHost("a")}, want: []string{"github.com/StevenCyb/golang-functional-options/options_test.setHost.func1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range options.Describe(tt.opts...) {
+				got = append(got, fmt.Sprint(d))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Describe() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if runs != 0 {
+		t.Errorf("Describe() ran a named option %d times", runs)
+	}
+}
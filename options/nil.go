@@ -0,0 +1,18 @@
+package options
+
+// NotNil returns opt, or an option that does nothing when opt is nil, so
+// conditionally assigned options can be passed around safely:
+//
+//	var opt options.Option[Client]
+//	if debug {
+//		opt = WithLogger(debugLogger)
+//	}
+//	client := New(baseURL, options.NotNil(opt))
+func NotNil[T any](opt Option[T]) Option[T] {
+	if opt == nil {
+		return noop[T]
+	}
+	return opt
+}
+
+func noop[T any](*T) {}
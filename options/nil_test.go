@@ -0,0 +1,34 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestNilOptions(t *testing.T) {
+	var nilOpt options.Option[server]
+	var nilOptE options.OptionE[server]
+	want := server{host: "a"}
+
+	if got := options.Apply(new(server), nilOpt, setHost("a"), options.NotNil(nilOpt)); !reflect.DeepEqual(*got, want) {
+		t.Errorf("Apply() = %+v, want %+v", *got, want)
+	}
+	got := new(server)
+	if err := options.ApplyE(got, nilOptE, options.E(setHost("a")), options.E(nilOpt)); err != nil || !reflect.DeepEqual(*got, want) {
+		t.Errorf("ApplyE() = %+v, %v, want %+v", *got, err, want)
+	}
+	wrapped := []options.Option[server]{
+		options.Combine(nilOpt, setHost("a")),
+		options.If(true, nilOpt),
+		options.Deprecated(nilOpt, "deprecated"),
+		options.Named("Nil", nil, nilOpt),
+		options.Lazy(func() options.Option[server] { return nil }),
+	}
+	options.SetDeprecationHook(func(string) {})
+	t.Cleanup(func() { options.SetDeprecationHook(nil) })
+	if got := options.Apply(new(server), wrapped...); !reflect.DeepEqual(*got, want) {
+		t.Errorf("Apply() = %+v, want %+v", *got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package options
+
+import "fmt"
+
+// ErrDuplicateOption is returned by ApplyE when an option guarded by Once
+// was supplied more than once.
+type ErrDuplicateOption struct {
+	Name string
+}
+
+func (e ErrDuplicateOption) Error() string {
+	return fmt.Sprintf("option %s supplied more than once", e.Name)
+}
+
+// Once guards the named options contained in opt, see Named, so ApplyE
+// fails with ErrDuplicateOption when an option of the same name is supplied
+// again in the same call, before or after opt. The options still run in the
+// order they were supplied, and the duplicate is reported once however many
+// times the name repeats.
+func Once[T any](opt Option[T]) OptionE[T] {
+	opt = NotNil(opt)
+	var names []string
+	found, _ := probe(opt)
+	for _, d := range found {
+		names = append(names, d.(NamedOption[T]).Name())
+	}
+	return func(t *T) error {
+		if s := scopeOf(t); s != nil && !s.probing {
+			if s.once == nil {
+				s.once = map[string]bool{}
+			}
+			for _, name := range names {
+				s.once[name] = true
+			}
+		}
+		opt(t)
+		return nil
+	}
+}
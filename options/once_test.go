@@ -0,0 +1,65 @@
+package options_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []options.OptionE[server]
+		want    string
+		wantDup string
+	}{
+		{name: "single", opts: []options.OptionE[server]{options.Once(withHost("a"))}, want: "a"},
+		{name: "other names", opts: []options.OptionE[server]{options.Once(withHost("a")), options.E(withTag("x"))}, want: "a"},
+		{
+			name:    "repeated after",
+			opts:    []options.OptionE[server]{options.Once(withHost("a")), options.E(withHost("b"))},
+			want:    "b",
+			wantDup: "WithHost",
+		},
+		{
+			name:    "repeated before",
+			opts:    []options.OptionE[server]{options.E(withHost("a")), options.Once(withHost("b"))},
+			want:    "b",
+			wantDup: "WithHost",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(server)
+			err := options.ApplyE(got, tt.opts...)
+			var dup options.ErrDuplicateOption
+			switch {
+			case tt.wantDup == "" && err != nil:
+				t.Errorf("ApplyE() error = %v, want nil", err)
+			case tt.wantDup != "" && (!errors.As(err, &dup) || dup.Name != tt.wantDup):
+				t.Errorf("ApplyE() error = %v, want a duplicate of %s", err, tt.wantDup)
+			}
+			if got.host != tt.want {
+				t.Errorf("host = %q, want %q", got.host, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnceOrder(t *testing.T) {
+	want := options.ErrDuplicateOption{Name: "WithHost"}.Error()
+	orders := map[string][]options.OptionE[server]{
+		"guard first":   {options.Once(withHost("a")), options.E(withHost("b"))},
+		"guard last":    {options.E(withHost("a")), options.Once(withHost("b"))},
+		"many repeats":  {options.E(withHost("a")), options.Once(withHost("b")), options.E(withHost("c"))},
+		"guarded twice": {options.Once(withHost("a")), options.Once(withHost("b"))},
+	}
+	for name, opts := range orders {
+		t.Run(name, func(t *testing.T) {
+			if err := options.ApplyE(new(server), opts...); err == nil || err.Error() != want {
+				t.Errorf("ApplyE() error = %v, want %s", err, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package options
+
+import (
+	"context"
+	"errors"
+)
+
+// Option configures a value of type T.
+type Option[T any] func(*T)
+
+// OptionE configures a value of type T and reports invalid input as an error.
+type OptionE[T any] func(*T) error
+
+// OptionCtx configures a value of type T and may perform I/O bound to ctx,
+// such as fetching a token or resolving a secret.
+type OptionCtx[T any] func(ctx context.Context, t *T) error
+
+// Apply runs all options in the given order against target and returns it.
+// Nil options are skipped. Options wrapped with WithPriority run after all
+// others, see WithPriority. When the target is a BeforeApplier or an
+// AfterApplier, its hooks run first and last.
+func Apply[T any](target *T, opts ...Option[T]) *T {
+	return apply(target, true, true, opts)
+}
+
+// apply is Apply with the BeforeApply and AfterApply hooks only run when
+// before and after are set, for callers whose target is already configured
+// or that run the hooks around several calls themselves.
+func apply[T any](target *T, before, after bool, opts []Option[T]) *T {
+	s, outer := begin(target)
+	defer end(target, s)
+	if outer && before {
+		beforeApply(target)
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(target)
+		}
+	}
+	if outer {
+		s.flush()
+		if after {
+			afterApply(context.Background(), target)
+		}
+	}
+	return target
+}
+
+// ApplyAll is Apply for any Expander such as an OptionSet, running the
+// options each item expands to, so plain options and sets can be mixed in
+// a single call:
+//
+//	options.ApplyAll(client, ProductionDefaults(), WithLogger(logger))
+func ApplyAll[T any](target *T, items ...Expander[T]) *T {
+	return Apply(target, Flatten(items...)...)
+}
+
+// ApplyE runs all options in the given order against target. Nil options
+// are skipped. Checks declared by options such as Required run once all
+// options and the AfterApply hook have run, followed by Validate when the
+// target is a Validator and nothing else failed.
+//
+// Every failure is reported at once through errors.Join, with the errors of
+// options wrapped in an OptionError naming the option.
+func ApplyE[T any](target *T, opts ...OptionE[T]) error {
+	return applyE(target, true, opts)
+}
+
+// applyE is ApplyE with the BeforeApply hook only run when before is set.
+func applyE[T any](target *T, before bool, opts []OptionE[T]) error {
+	s, outer := begin(target)
+	defer end(target, s)
+	if outer && before {
+		beforeApply(target)
+	}
+
+	var errs []error
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if outer {
+			s.supplied++
+		}
+		if err := opt(target); err != nil {
+			errs = append(errs, optionError(opt, err))
+		}
+	}
+	if outer {
+		return s.complete(context.Background(), target, errs)
+	}
+	return errors.Join(errs...)
+}
+
+// E turns an Option into an OptionE that never fails, so plain options can
+// be mixed with fallible ones in a single ApplyE call.
+func E[T any](opt Option[T]) OptionE[T] {
+	if opt == nil {
+		return nil
+	}
+	return func(t *T) error {
+		opt(t)
+		return nil
+	}
+}
+
+// ApplyCtx runs all options in the given order against target and stops as
+// soon as ctx is done. Nil options are skipped. Hooks, checks, validation
+// and error reporting work as for ApplyE, with ctx passed to the AfterApply
+// hook.
+func ApplyCtx[T any](ctx context.Context, target *T, opts ...OptionCtx[T]) error {
+	s, outer := begin(target)
+	defer end(target, s)
+	if outer {
+		beforeApply(target)
+	}
+
+	var errs []error
+	for _, opt := range opts {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if opt == nil {
+			continue
+		}
+		if outer {
+			s.supplied++
+		}
+		if err := opt(ctx, target); err != nil {
+			errs = append(errs, optionError(opt, err))
+		}
+	}
+	if outer {
+		return s.complete(ctx, target, errs)
+	}
+	return errors.Join(errs...)
+}
+
+// Ctx turns an OptionE into an OptionCtx that ignores the context, so it
+// can be mixed with context aware options in a single ApplyCtx call.
+func Ctx[T any](opt OptionE[T]) OptionCtx[T] {
+	if opt == nil {
+		return nil
+	}
+	return func(_ context.Context, t *T) error {
+		return opt(t)
+	}
+}
@@ -0,0 +1,135 @@
+package options_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type server struct {
+	host   string
+	port   int
+	tags   []string
+	limits limits
+}
+
+type limits struct {
+	maxConns int
+}
+
+func setHost(host string) options.Option[server] {
+	return func(s *server) { s.host = host }
+}
+
+var errPort = errors.New("port out of range")
+
+func setPort(port int) options.OptionE[server] {
+	return func(s *server) error {
+		if port < 1 || port > 65535 {
+			return errPort
+		}
+		s.port = port
+		return nil
+	}
+}
+
+func addTag(tag string) options.Option[server] {
+	return func(s *server) { s.tags = append(s.tags, tag) }
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []options.Option[server]
+		want server
+	}{
+		{name: "no options", want: server{}},
+		{name: "in order", opts: []options.Option[server]{setHost("a"), setHost("b")}, want: server{host: "b"}},
+		{name: "accumulating", opts: []options.Option[server]{addTag("x"), addTag("y")}, want: server{tags: []string{"x", "y"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := options.Apply(new(server), tt.opts...)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyE(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []options.OptionE[server]
+		want    server
+		wantErr error
+	}{
+		{name: "no options", want: server{}},
+		{name: "valid", opts: []options.OptionE[server]{setPort(8080), options.E(setHost("a"))}, want: server{host: "a", port: 8080}},
+		{name: "invalid", opts: []options.OptionE[server]{options.E(setHost("a")), setPort(0)}, want: server{host: "a"}, wantErr: errPort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(server)
+			err := options.ApplyE(got, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApplyE() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ApplyE() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCtx(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	fromCtx := func(ctx context.Context, s *server) error {
+		s.host = ctx.Value(hostKey{}).(string)
+		return nil
+	}
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		opts    []options.OptionCtx[server]
+		want    server
+		wantErr error
+	}{
+		{
+			name: "valid",
+			ctx:  context.WithValue(context.Background(), hostKey{}, "a"),
+			opts: []options.OptionCtx[server]{fromCtx, options.Ctx(setPort(80))},
+			want: server{host: "a", port: 80},
+		},
+		{
+			name:    "invalid",
+			ctx:     context.WithValue(context.Background(), hostKey{}, "a"),
+			opts:    []options.OptionCtx[server]{fromCtx, options.Ctx(setPort(0)), options.Ctx(setPort(80))},
+			want:    server{host: "a", port: 80},
+			wantErr: errPort,
+		},
+		{
+			name:    "canceled",
+			ctx:     canceled,
+			opts:    []options.OptionCtx[server]{options.Ctx(setPort(80))},
+			wantErr: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(server)
+			if err := options.ApplyCtx(tt.ctx, got, tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApplyCtx() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ApplyCtx() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+type hostKey struct{}
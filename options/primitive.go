@@ -0,0 +1,108 @@
+package options
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// String assigns v with surrounding whitespace removed to the field returned
+// by get.
+func String[T any](get func(*T) *string, v string) Option[T] {
+	return Set(get, strings.TrimSpace(v))
+}
+
+// IntRange assigns v clamped to [lo, hi] to the field returned by get.
+func IntRange[T any](get func(*T) *int, v, lo, hi int) Option[T] {
+	return Set(get, min(max(v, lo), hi))
+}
+
+// Duration parses s as a time.Duration, such as "30s", and assigns it to the
+// field returned by get.
+func Duration[T any](get func(*T) *time.Duration, s string) OptionE[T] {
+	return func(t *T) error {
+		d, err := ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*get(t) = d
+		return nil
+	}
+}
+
+// ParseDuration parses s, with surrounding whitespace removed, as a
+// time.Duration such as "1m30s". It backs the string forms of the options
+// generated for duration fields, such as WithTimeoutString("30s").
+func ParseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	return d, nil
+}
+
+// Integer is the constraint of the sizes parsed by ParseSize.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// sizeUnits are the units accepted by ParseSize, in lower case.
+var sizeUnits = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1e3, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1e6, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1e9, "gib": 1 << 30,
+	"t": 1 << 40, "tb": 1e12, "tib": 1 << 40,
+	"p": 1 << 50, "pb": 1e15, "pib": 1 << 50,
+}
+
+// ParseSize parses s as a number of bytes, such as "512", "64KB" or
+// "1.5GiB". The units KB, MB, GB, TB and PB are powers of 1000, KiB, MiB,
+// GiB, TiB and PiB as well as the short forms K, M, G, T and P powers of
+// 1024; they are case insensitive and may be separated by a space. Sizes
+// of fractional bytes or out of the range of N fail. It
+// backs the string forms of the options generated for fields tagged
+// `option:"size"`, such as WithCacheSizeString("64MiB").
+func ParseSize[N Integer](s string) (N, error) {
+	text := strings.TrimSpace(s)
+	i := strings.IndexFunc(text, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(text)
+	}
+	unit, ok := sizeUnits[strings.ToLower(strings.TrimSpace(text[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, strings.TrimSpace(text[i:]))
+	}
+	f, err := strconv.ParseFloat(text[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	f *= unit
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("invalid size %q: not a whole number of bytes", s)
+	}
+	// Converting floats beyond the range of N is implementation specific.
+	if f >= math.Ldexp(1, 64) {
+		return 0, fmt.Errorf("invalid size %q: out of range", s)
+	}
+	n := N(f)
+	if float64(n) != f {
+		return 0, fmt.Errorf("invalid size %q: out of range", s)
+	}
+	return n, nil
+}
+
+// Bool parses s as a boolean, accepting the forms of strconv.ParseBool, and
+// assigns it to the field returned by get.
+func Bool[T any](get func(*T) *bool, s string) OptionE[T] {
+	return func(t *T) error {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q: %w", s, err)
+		}
+		*get(t) = b
+		return nil
+	}
+}
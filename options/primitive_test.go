@@ -0,0 +1,64 @@
+package options_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type primitives struct {
+	name    string
+	workers int
+	timeout time.Duration
+	debug   bool
+}
+
+func TestPrimitives(t *testing.T) {
+	name := func(p *primitives) *string { return &p.name }
+	workers := func(p *primitives) *int { return &p.workers }
+	tests := []struct {
+		name string
+		opt  options.Option[primitives]
+		want primitives
+	}{
+		{name: "String", opt: options.String(name, "  a \n"), want: primitives{name: "a"}},
+		{name: "IntRange within", opt: options.IntRange(workers, 4, 1, 8), want: primitives{workers: 4}},
+		{name: "IntRange below", opt: options.IntRange(workers, 0, 1, 8), want: primitives{workers: 1}},
+		{name: "IntRange above", opt: options.IntRange(workers, 9, 1, 8), want: primitives{workers: 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := options.Apply(new(primitives), tt.opt); *got != tt.want {
+				t.Errorf("Apply() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsedPrimitives(t *testing.T) {
+	timeout := func(p *primitives) *time.Duration { return &p.timeout }
+	debug := func(p *primitives) *bool { return &p.debug }
+	tests := []struct {
+		name    string
+		opt     options.OptionE[primitives]
+		want    primitives
+		wantErr string
+	}{
+		{name: "Duration", opt: options.Duration(timeout, "1m30s"), want: primitives{timeout: 90 * time.Second}},
+		{name: "Duration invalid", opt: options.Duration(timeout, "soon"), wantErr: `invalid duration "soon": time: invalid duration "soon"`},
+		{name: "Bool", opt: options.Bool(debug, "t"), want: primitives{debug: true}},
+		{name: "Bool invalid", opt: options.Bool(debug, "yes"), wantErr: `invalid boolean "yes": strconv.ParseBool: parsing "yes": invalid syntax`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(primitives)
+			if err := errorString(tt.opt(got)); err != tt.wantErr {
+				t.Fatalf("option error = %q, want %q", err, tt.wantErr)
+			}
+			if *got != tt.want {
+				t.Errorf("option result = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
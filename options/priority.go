@@ -0,0 +1,25 @@
+package options
+
+// WithPriority defers opt until all options without a priority have been
+// applied. Deferred options run in ascending priority, so the option with
+// the highest priority has the final say, and options with equal priority
+// keep the order in which they were supplied. This lets defaults,
+// environment derived options and explicit options share one slice:
+//
+//	options.Apply(client,
+//		options.WithPriority(options.Combine(explicit...), 30),
+//		options.WithPriority(options.Combine(fromEnv...), 20),
+//		options.WithPriority(options.Combine(defaults...), 10),
+//	)
+//
+// Outside of an apply call the option is applied immediately.
+func WithPriority[T any](opt Option[T], priority int) Option[T] {
+	opt = NotNil(opt)
+	return func(t *T) {
+		if s := scopeOf(t); s != nil && !s.probing && !s.flushing {
+			s.deferred = append(s.deferred, deferredOption{priority: priority, run: func() { opt(t) }})
+			return
+		}
+		opt(t)
+	}
+}
@@ -0,0 +1,55 @@
+package options_test
+
+import (
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestWithPriority(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []options.Option[server]
+		want string
+	}{
+		{
+			name: "highest priority wins",
+			opts: []options.Option[server]{
+				options.WithPriority(setHost("explicit"), 30),
+				options.WithPriority(setHost("env"), 20),
+				options.WithPriority(setHost("default"), 10),
+			},
+			want: "explicit",
+		},
+		{
+			name: "after options without priority",
+			opts: []options.Option[server]{options.WithPriority(setHost("a"), 0), setHost("b")},
+			want: "a",
+		},
+		{
+			name: "equal priority keeps the order",
+			opts: []options.Option[server]{options.WithPriority(setHost("a"), 1), options.WithPriority(setHost("b"), 1)},
+			want: "b",
+		},
+		{
+			name: "nested in Combine",
+			opts: []options.Option[server]{options.Combine(options.WithPriority(setHost("a"), 2), setHost("b")), options.WithPriority(setHost("c"), 1)},
+			want: "a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := options.Apply(new(server), tt.opts...); got.host != tt.want {
+				t.Errorf("host = %q, want %q", got.host, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPriorityOutsideApply(t *testing.T) {
+	s := new(server)
+	options.WithPriority(setHost("a"), 1)(s)
+	if s.host != "a" {
+		t.Errorf("host = %q, want %q", s.host, "a")
+	}
+}
@@ -0,0 +1,10 @@
+package options
+
+// Reconfigure applies opts to an already constructed value, which gives
+// long lived values such as clients a supported way to be tuned after
+// construction. The BeforeApply hook is skipped so the existing state is
+// kept. When *T is a Validator it is validated once all options have been
+// applied.
+func Reconfigure[T any](existing *T, opts ...Option[T]) error {
+	return applyE(existing, false, []OptionE[T]{E(Combine(opts...))})
+}
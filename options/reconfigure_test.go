@@ -0,0 +1,48 @@
+package options_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+var errNoHost = errors.New("no host")
+
+type validated struct {
+	host string
+}
+
+func (v *validated) Validate() error {
+	if v.host == "" {
+		return errNoHost
+	}
+	return nil
+}
+
+func TestReconfigure(t *testing.T) {
+	setName := func(host string) options.Option[validated] {
+		return func(v *validated) { v.host = host }
+	}
+	tests := []struct {
+		name    string
+		opts    []options.Option[validated]
+		want    string
+		wantErr error
+	}{
+		{name: "valid", opts: []options.Option[validated]{setName("b")}, want: "b"},
+		{name: "unchanged", want: "a"},
+		{name: "invalid", opts: []options.Option[validated]{setName("")}, wantErr: errNoHost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validated{host: "a"}
+			if err := options.Reconfigure(v, tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Reconfigure() error = %v, want %v", err, tt.wantErr)
+			}
+			if v.host != tt.want {
+				t.Errorf("host = %q, want %q", v.host, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package options
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+)
+
+type registration struct {
+	valueType reflect.Type
+	build     func(v reflect.Value) any
+	// named is the name the constructor gives its options, see Named, if
+	// any.
+	named string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[reflect.Type]map[string]registration{}
+)
+
+// Register makes the option constructor ctor available for T under name,
+// so options can be looked up by name in plugin systems and config driven
+// construction. It panics when name is registered twice for the same T.
+// name may differ from the name the constructor gives its options, such as
+// timeout for WithTimeout; OptionSet.MarshalJSON then writes name.
+//
+//	func init() {
+//		options.Register("header", WithHeader)
+//	}
+func Register[T, V any](name string, ctor func(V) Option[T]) {
+	named := namedBy(ctor)
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	target := reflect.TypeFor[T]()
+	if registry[target] == nil {
+		registry[target] = map[string]registration{}
+	}
+	if _, dup := registry[target][name]; dup {
+		panic(fmt.Sprintf("options: Register called twice for %s option %q", target, name))
+	}
+	registry[target][name] = registration{
+		valueType: reflect.TypeFor[V](),
+		build: func(v reflect.Value) any {
+			var value V
+			reflect.ValueOf(&value).Elem().Set(v)
+			return ctor(value)
+		},
+		named: named,
+	}
+}
+
+// namedBy returns the name of the option ctor builds from the zero value of
+// V, or "" when it does not build a single named option.
+func namedBy[T, V any](ctor func(V) Option[T]) (name string) {
+	defer func() {
+		// Constructors may reject the zero value.
+		_ = recover()
+	}()
+	var zero V
+	opt := ctor(zero)
+	if opt == nil {
+		return ""
+	}
+	if found, _ := probe(opt); len(found) == 1 {
+		return found[0].(NamedOption[T]).Name()
+	}
+	return ""
+}
+
+// registeredName returns the name the constructor of the option named name
+// is registered under for T, preferring name itself. Names without a
+// registered constructor are returned as they are.
+func registeredName[T any](name string) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	regs := registry[reflect.TypeFor[T]()]
+	if _, ok := regs[name]; ok {
+		return name
+	}
+	var keys []string
+	for key, reg := range regs {
+		if reg.named == name {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return name
+	}
+	sort.Strings(keys)
+	return keys[0]
+}
+
+// Lookup builds the option registered for T under name from value. The
+// value is converted to the parameter type of the constructor as described
+// for FromMap.
+func Lookup[T any](name string, value any) (Option[T], error) {
+	registryMu.RLock()
+	reg, ok := registry[reflect.TypeFor[T]()][name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown option %q", name)
+	}
+
+	v, err := fields.Convert(value, reg.valueType)
+	if err != nil {
+		return nil, fmt.Errorf("option %q: %w", name, err)
+	}
+	return reg.build(v).(Option[T]), nil
+}
+
+// Registered returns the sorted names of the options registered for T.
+func Registered[T any]() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var names []string
+	for name := range registry[reflect.TypeFor[T]()] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
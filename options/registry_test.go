@@ -0,0 +1,69 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+type registered struct {
+	host string
+	port uint16
+}
+
+func init() {
+	options.Register("host", func(host string) options.Option[registered] {
+		return func(r *registered) { r.host = host }
+	})
+	options.Register("port", func(port uint16) options.Option[registered] {
+		return func(r *registered) { r.port = port }
+	})
+}
+
+func TestRegistered(t *testing.T) {
+	if got, want := options.Registered[registered](), []string{"host", "port"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Registered() = %q, want %q", got, want)
+	}
+	if got := options.Registered[server](); got != nil {
+		t.Errorf("Registered() = %q, want none", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   any
+		want    registered
+		wantErr string
+	}{
+		{name: "exact type", key: "host", value: "a", want: registered{host: "a"}},
+		{name: "converted", key: "port", value: "8080", want: registered{port: 8080}},
+		{name: "unknown", key: "tls", value: true, wantErr: `unknown option "tls"`},
+		{name: "unconvertible", key: "port", value: true, wantErr: `option "port": cannot use bool as uint16`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, err := options.Lookup[registered](tt.key, tt.value)
+			if got := errorString(err); got != tt.wantErr {
+				t.Fatalf("Lookup() error = %q, want %q", got, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := options.Apply(new(registered), opt); *got != tt.want {
+				t.Errorf("Apply(Lookup()) = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() did not panic for a duplicate name")
+		}
+	}()
+	options.Register("host", func(string) options.Option[registered] { return nil })
+}
@@ -0,0 +1,54 @@
+package options
+
+import "fmt"
+
+// ErrMissingRequiredOption is returned by ApplyE when a required option was
+// not supplied.
+type ErrMissingRequiredOption struct {
+	Name string
+}
+
+func (e ErrMissingRequiredOption) Error() string {
+	return fmt.Sprintf("missing required option %s", e.Name)
+}
+
+// Required declares that the option called name is mandatory. wasSet reports
+// whether the option has configured the target. The check runs after all
+// other options of the same ApplyE call, so its position in the list does
+// not matter.
+func Required[T any](name string, wasSet func(*T) bool) OptionE[T] {
+	return func(t *T) error {
+		check := func() error {
+			if !wasSet(t) {
+				return ErrMissingRequiredOption{Name: name}
+			}
+			return nil
+		}
+		if s := scopeOf(t); s != nil {
+			s.policy()
+			s.checks = append(s.checks, check)
+			return nil
+		}
+		return check()
+	}
+}
+
+// RequiredOption declares that the option called name, see Named and NamedE,
+// must be supplied in the same ApplyE call. Like Required, the check runs
+// after all other options.
+func RequiredOption[T any](name string) OptionE[T] {
+	return func(t *T) error {
+		s := scopeOf(t)
+		if s == nil {
+			return ErrMissingRequiredOption{Name: name}
+		}
+		s.policy()
+		s.checks = append(s.checks, func() error {
+			if s.seen[name] == 0 {
+				return ErrMissingRequiredOption{Name: name}
+			}
+			return nil
+		})
+		return nil
+	}
+}
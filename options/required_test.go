@@ -0,0 +1,37 @@
+package options_test
+
+import (
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestRequired(t *testing.T) {
+	hostSet := options.Required("WithHost", func(s *server) bool { return s.host != "" })
+	tests := []struct {
+		name    string
+		opts    []options.OptionE[server]
+		wantErr string
+	}{
+		{name: "supplied", opts: []options.OptionE[server]{hostSet, options.E(setHost("a"))}},
+		{name: "supplied before the check", opts: []options.OptionE[server]{options.E(setHost("a")), hostSet}},
+		{name: "missing", opts: []options.OptionE[server]{hostSet, setPort(80)}, wantErr: "missing required option WithHost"},
+		{name: "by name", opts: []options.OptionE[server]{options.RequiredOption[server]("WithHost"), options.E(withHost(""))}},
+		{name: "by name missing", opts: []options.OptionE[server]{options.RequiredOption[server]("WithHost"), setPort(80)}, wantErr: "missing required option WithHost"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := options.ApplyE(new(server), tt.opts...)
+			if got := errorString(err); got != tt.wantErr {
+				t.Errorf("ApplyE() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
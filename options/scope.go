@@ -0,0 +1,132 @@
+package options
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// scope holds the state shared by all options of a single apply call on one
+// target. Options reach it through the target pointer they are applied to.
+type scope struct {
+	depth  int
+	checks []func() error
+	errs   []error
+
+	// supplied counts the options passed to the outermost apply call, of
+	// which policies are declarations such as Required rather than
+	// configuration.
+	supplied int
+	policies int
+
+	// seen counts the named options applied so far and once marks the
+	// names that may only be supplied a single time.
+	seen map[string]int
+	once map[string]bool
+
+	// deferred holds prioritized options until the outermost apply call
+	// runs them in order of their priority.
+	deferred []deferredOption
+	flushing bool
+
+	// probing is set by Describe; named options record themselves into
+	// described instead of configuring the target.
+	probing   bool
+	described []any
+	tags      map[string]string
+}
+
+var scopes sync.Map // map[any]*scope
+
+// begin opens the scope for target, joining an already open one when apply
+// calls are nested. The returned flag reports whether this is the outermost
+// call that owns the scope.
+func begin(target any) (*scope, bool) {
+	if v, ok := scopes.Load(target); ok {
+		s := v.(*scope)
+		s.depth++
+		return s, false
+	}
+	s := &scope{depth: 1}
+	scopes.Store(target, s)
+	return s, true
+}
+
+// end closes one level of the scope for target.
+func end(target any, s *scope) {
+	s.depth--
+	if s.depth == 0 {
+		scopes.Delete(target)
+	}
+}
+
+// scopeOf returns the open scope for target or nil when the option is
+// invoked outside of an apply call.
+func scopeOf(target any) *scope {
+	if v, ok := scopes.Load(target); ok {
+		return v.(*scope)
+	}
+	return nil
+}
+
+type deferredOption struct {
+	priority int
+	run      func()
+}
+
+// flush runs the deferred options in ascending priority. Options with the
+// same priority keep the order in which they were supplied.
+func (s *scope) flush() {
+	s.flushing = true
+	defer func() { s.flushing = false }()
+
+	slices.SortStableFunc(s.deferred, func(a, b deferredOption) int {
+		return cmp.Compare(a.priority, b.priority)
+	})
+	for _, d := range s.deferred {
+		d.run()
+	}
+	s.deferred = nil
+}
+
+// policy notes that a declaration such as Required was supplied to the
+// outermost apply call.
+func (s *scope) policy() {
+	if s.depth == 1 {
+		s.policies++
+	}
+}
+
+// record notes that the named option was applied and reports the first
+// repetition of a name guarded by Once.
+func (s *scope) record(name string) {
+	if s.seen == nil {
+		s.seen = map[string]int{}
+	}
+	s.seen[name]++
+	dup := error(ErrDuplicateOption{Name: name})
+	if s.once[name] && s.seen[name] > 1 && !slices.Contains(s.errs, dup) {
+		s.errs = append(s.errs, dup)
+	}
+}
+
+// complete ends the outermost apply call on target: it runs the deferred
+// options and the AfterApply hook, then the checks. The target is only
+// validated when neither the options in errs nor the checks failed.
+func (s *scope) complete(ctx context.Context, target any, errs []error) error {
+	s.flush()
+	afterApply(ctx, target)
+
+	errs = append(errs, s.errs...)
+	for _, check := range s.checks {
+		if err := check(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return validate(target)
+}
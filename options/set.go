@@ -0,0 +1,73 @@
+package options
+
+// OptionSet is a reusable collection of options for T, such as company wide
+// defaults that services layer their own options on top of. The methods of
+// OptionSet never modify the receiver.
+type OptionSet[T any] []Option[T]
+
+// NewSet creates an option set from opts.
+func NewSet[T any](opts ...Option[T]) OptionSet[T] {
+	return OptionSet[T](opts).Clone()
+}
+
+// Apply runs the options of the set against target and returns it.
+func (s OptionSet[T]) Apply(target *T) *T {
+	return Apply(target, s...)
+}
+
+// Option bundles the set into a single option.
+func (s OptionSet[T]) Option() Option[T] {
+	return Combine(s.Clone()...)
+}
+
+// Clone returns a copy of the set that can be extended independently.
+func (s OptionSet[T]) Clone() OptionSet[T] {
+	if s == nil {
+		return nil
+	}
+	return append(OptionSet[T](nil), s...)
+}
+
+// Append returns a new set with opts added after the options of s.
+func (s OptionSet[T]) Append(opts ...Option[T]) OptionSet[T] {
+	return append(s.Clone(), opts...)
+}
+
+// Merge returns a new set with the options of all others added after the
+// options of s.
+func (s OptionSet[T]) Merge(others ...OptionSet[T]) OptionSet[T] {
+	merged := s.Clone()
+	for _, other := range others {
+		merged = append(merged, other...)
+	}
+	return merged
+}
+
+// Dedup returns a new set in which only the last option of every name is
+// kept, see Named. Options without a name are always kept.
+func (s OptionSet[T]) Dedup() OptionSet[T] {
+	names := make([][]string, len(s))
+	last := map[string]int{}
+	for i, opt := range s {
+		found, _ := probe(opt)
+		for _, d := range found {
+			name := d.(NamedOption[T]).Name()
+			names[i] = append(names[i], name)
+			last[name] = i
+		}
+	}
+
+	var deduped OptionSet[T]
+	for i, opt := range s {
+		keep := len(names[i]) == 0
+		for _, name := range names[i] {
+			if last[name] == i {
+				keep = true
+			}
+		}
+		if keep {
+			deduped = append(deduped, opt)
+		}
+	}
+	return deduped
+}
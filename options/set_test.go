@@ -0,0 +1,55 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestOptionSet(t *testing.T) {
+	base := options.NewSet(withHost("a"))
+	appended := base.Append(withTag("x"))
+	merged := base.Merge(options.NewSet(withTag("y")), appended)
+
+	tests := []struct {
+		name string
+		set  options.OptionSet[server]
+		want server
+	}{
+		{name: "base untouched", set: base, want: server{host: "a"}},
+		{name: "appended", set: appended, want: server{host: "a", tags: []string{"x"}}},
+		{name: "merged", set: merged, want: server{host: "a", tags: []string{"y", "x"}}},
+		{name: "option", set: options.OptionSet[server]{merged.Option()}, want: server{host: "a", tags: []string{"y", "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set.Apply(new(server)); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionSetDedup(t *testing.T) {
+	tests := []struct {
+		name string
+		set  options.OptionSet[server]
+		want server
+	}{
+		{name: "last of a name", set: options.NewSet(withHost("a"), withTag("x"), withHost("b")), want: server{host: "b", tags: []string{"x"}}},
+		{name: "unnamed kept", set: options.NewSet(addTag("x"), addTag("y")), want: server{tags: []string{"x", "y"}}},
+		{
+			name: "bundle kept for any last name",
+			set:  options.NewSet(withHost("a"), options.Combine(withTag("x"), withHost("b")), withTag("y")),
+			want: server{host: "b", tags: []string{"x", "y"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set.Dedup().Apply(new(server)); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Dedup().Apply() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package options_test
+
+import (
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    int64
+		wantErr string
+	}{
+		{s: "512", want: 512},
+		{s: " 64KB ", want: 64000},
+		{s: "64 kib", want: 64 << 10},
+		{s: "1.5GiB", want: 3 << 29},
+		{s: "2M", want: 2 << 20},
+		{s: "1PB", want: 1e15},
+		{s: "", wantErr: `invalid size "": strconv.ParseFloat: parsing "": invalid syntax`},
+		{s: "1.5", wantErr: `invalid size "1.5": not a whole number of bytes`},
+		{s: "10 parsecs", wantErr: `invalid size "10 parsecs": unknown unit "parsecs"`},
+		{s: "-1", wantErr: `invalid size "-1": unknown unit "-1"`},
+		{s: "8192PiB", wantErr: `invalid size "8192PiB": out of range`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := options.ParseSize[int64](tt.s)
+			if gotErr := errorString(err); gotErr != tt.wantErr {
+				t.Fatalf("ParseSize() error = %q, want %q", gotErr, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := options.ParseSize[uint8]("256"); err == nil {
+		t.Error("ParseSize[uint8](256) succeeded")
+	}
+	if got, err := options.ParseSize[uint64]("16383PiB"); err != nil || got != 16383<<50 {
+		t.Errorf("ParseSize[uint64](16383PiB) = %d, %v", got, err)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	if got, err := options.ParseDuration(" 1m30s "); err != nil || got.String() != "1m30s" {
+		t.Errorf("ParseDuration() = %v, %v, want 1m30s", got, err)
+	}
+	if _, err := options.ParseDuration("soon"); err == nil {
+		t.Error("ParseDuration(soon) succeeded")
+	}
+}
@@ -0,0 +1,36 @@
+package options
+
+// Tag attaches the metadata key=value to opt, such as marking an option as
+// sensitive for redaction or audit tooling. The tag is reported for every
+// named option contained in opt by Describe and for opt as a whole by Tags.
+//
+//	options.Tag(WithHeader(header), "sensitive", "true")
+func Tag[T any](opt Option[T], key, value string) Option[T] {
+	opt = NotNil(opt)
+	return func(t *T) {
+		s := scopeOf(t)
+		if s == nil || !s.probing {
+			opt(t)
+			return
+		}
+
+		start := len(s.described)
+		opt(t)
+		for i := start; i < len(s.described); i++ {
+			s.described[i] = s.described[i].(*namedOption[T]).withTag(key, value)
+		}
+		if s.tags == nil {
+			s.tags = map[string]string{}
+		}
+		s.tags[key] = value
+	}
+}
+
+// Tags returns the metadata attached to opt and the options it contains.
+func Tags[T any](opt Option[T]) map[string]string {
+	if opt == nil {
+		return nil
+	}
+	_, tags := probe(opt)
+	return tags
+}
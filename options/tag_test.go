@@ -0,0 +1,43 @@
+package options_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestTag(t *testing.T) {
+	tagged := options.Tag(options.Combine(withHost("a"), withTag("x")), "sensitive", "true")
+	if got := options.Apply(new(server), tagged); got.host != "a" {
+		t.Errorf("host = %q, want %q", got.host, "a")
+	}
+
+	want := map[string]string{"sensitive": "true"}
+	if got := options.Tags(tagged); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tags() = %v, want %v", got, want)
+	}
+	described := options.Describe(tagged, withHost("b"))
+	if len(described) != 3 {
+		t.Fatalf("Describe() returned %d options, want 3", len(described))
+	}
+	for i, d := range described[:2] {
+		if got := d.Tags(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Describe()[%d].Tags() = %v, want %v", i, got, want)
+		}
+	}
+	if got := described[2].Tags(); len(got) != 0 {
+		t.Errorf("Describe()[2].Tags() = %v, want none", got)
+	}
+}
+
+func TestTagsNested(t *testing.T) {
+	opt := options.Tag(options.Tag(withHost("a"), "audit", "yes"), "sensitive", "true")
+	want := map[string]string{"audit": "yes", "sensitive": "true"}
+	if got := options.Tags(opt); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tags() = %v, want %v", got, want)
+	}
+	if got := options.Tags(withHost("a")); len(got) != 0 {
+		t.Errorf("Tags() = %v, want none", got)
+	}
+}
@@ -0,0 +1,22 @@
+package options
+
+import "github.com/StevenCyb/golang-functional-options/internal/fields"
+
+// ApplyTx applies opts to a deep copy of target and only writes the result
+// back when every option succeeds and, if *T is a Validator, the result
+// validates. A failed call leaves target untouched, so a half configured
+// value is never observable. The BeforeApply hook is skipped, as the copy
+// carries the configuration of target.
+//
+// Structs, slices, maps and pointers to types of the package declaring T
+// are copied. Pointers to types of other packages, interfaces, functions
+// and channels are shared between the copy and target, so options should
+// replace such values instead of modifying them in place.
+func ApplyTx[T any](target *T, opts ...OptionE[T]) error {
+	candidate := fields.Copy(target)
+	if err := applyE(candidate, false, opts); err != nil {
+		return err
+	}
+	*target = *candidate
+	return nil
+}
@@ -0,0 +1,54 @@
+package options_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestApplyTx(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []options.OptionE[server]
+		want    server
+		wantErr error
+	}{
+		{
+			name: "committed",
+			opts: []options.OptionE[server]{options.E(setHost("b")), options.E(addTag("y")), setPort(80)},
+			want: server{host: "b", port: 80, tags: []string{"x", "y"}},
+		},
+		{
+			name:    "rolled back",
+			opts:    []options.OptionE[server]{options.E(setHost("b")), options.E(addTag("y")), setPort(0)},
+			want:    server{host: "a", tags: []string{"x"}},
+			wantErr: errPort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Spare capacity makes an append on a shared slice visible.
+			tags := append(make([]string, 0, 2), "x")
+			got := server{host: "a", tags: tags}
+			if err := options.ApplyTx(&got, tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApplyTx() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyTx() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantErr != nil && tags[:2][1] != "" {
+				t.Errorf("ApplyTx() appended %q to the tags of target", tags[:2][1])
+			}
+		})
+	}
+}
+
+func TestApplyTxValidate(t *testing.T) {
+	v := validated{host: "a"}
+	err := options.ApplyTx(&v, func(v *validated) error { v.host = ""; return nil })
+	if !errors.Is(err, errNoHost) || v.host != "a" {
+		t.Errorf("ApplyTx() = %+v, %v, want the value untouched and %v", v, err, errNoHost)
+	}
+}
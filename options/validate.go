@@ -0,0 +1,15 @@
+package options
+
+// Validator is implemented by targets that check their invariants. ApplyE,
+// ApplyCtx and the functions built on them call Validate once all options
+// have been applied.
+type Validator interface {
+	Validate() error
+}
+
+func validate(target any) error {
+	if v, ok := target.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package options_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+func TestValidator(t *testing.T) {
+	setName := func(host string) options.OptionE[validated] {
+		return func(v *validated) error { v.host = host; return nil }
+	}
+	tests := []struct {
+		name    string
+		opts    []options.OptionE[validated]
+		wantErr error
+	}{
+		{name: "valid", opts: []options.OptionE[validated]{setName("a")}},
+		{name: "invalid", opts: []options.OptionE[validated]{setName("")}, wantErr: errNoHost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := options.ApplyE(new(validated), tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ApplyE() error = %v, want %v", err, tt.wantErr)
+			}
+			var ctxOpts []options.OptionCtx[validated]
+			for _, opt := range tt.opts {
+				ctxOpts = append(ctxOpts, options.Ctx(opt))
+			}
+			if err := options.ApplyCtx(context.Background(), new(validated), ctxOpts...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ApplyCtx() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
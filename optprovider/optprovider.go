@@ -0,0 +1,113 @@
+// Package optprovider merges the options of several sources, such as the
+// environment, a config file and the command line, with a declared
+// precedence, and reports which source configured each field:
+//
+//	layers, err := optprovider.Merge(
+//		optprovider.File[Client]("client.yaml"),
+//		optprovider.Env[Client]("CLIENT"),
+//		optprovider.Flags(parsed),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	client := new(Client)
+//	provenance := layers.Apply(client)
+//	// provenance["baseURL"] is "flags" when -base-url was given.
+//
+// Later providers take precedence over earlier ones, as with
+// options.Layers, so the example reads as "flags beat the environment beat
+// the file".
+package optprovider
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/optenv"
+	"github.com/StevenCyb/golang-functional-options/optfile"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// Provider is a named source of options for T.
+type Provider[T any] struct {
+	// Name identifies the provider in the provenance and in errors.
+	Name string
+	// Load returns the options of the source.
+	Load func() ([]options.Option[T], error)
+	// Files are the files read by Load, which Watch reloads the provider
+	// for when they change.
+	Files []string
+}
+
+// New creates a provider called name loading its options with load.
+func New[T any](name string, load func() ([]options.Option[T], error)) Provider[T] {
+	return Provider[T]{Name: name, Load: load}
+}
+
+// Static creates a provider called name of fixed options, such as the
+// options passed explicitly by the caller.
+func Static[T any](name string, opts ...options.Option[T]) Provider[T] {
+	return New(name, func() ([]options.Option[T], error) {
+		return opts, nil
+	})
+}
+
+// Env creates a provider called env reading the environment variables with
+// prefix, see optenv.Provide.
+func Env[T any](prefix string, opts ...options.Option[optenv.Config]) Provider[T] {
+	return New("env", func() ([]options.Option[T], error) {
+		return optenv.Provide[T](prefix, opts...)
+	})
+}
+
+// File creates a provider called file reading the file at path with the
+// optfile provider of its extension: .json, .yaml, .yml or .toml.
+func File[T any](path string, opts ...options.Option[optfile.Config]) Provider[T] {
+	p := New("file", func() ([]options.Option[T], error) {
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".json":
+			return optfile.JSON[T](path, opts...)
+		case ".yaml", ".yml":
+			return optfile.YAML[T](path, opts...)
+		case ".toml":
+			return optfile.TOML[T](path, opts...)
+		default:
+			return nil, fmt.Errorf("unknown file extension %q", ext)
+		}
+	})
+	p.Files = []string{path}
+	return p
+}
+
+// Flags creates a provider called flags of the options returned by parsed
+// once the flags are parsed, such as the function returned by optflag.Bind.
+func Flags[T any](parsed func() []options.Option[T]) Provider[T] {
+	return New("flags", func() ([]options.Option[T], error) {
+		return parsed(), nil
+	})
+}
+
+// Merge loads the providers and returns a layer of options per provider,
+// in the given order of increasing precedence. Applying the layers reports
+// the provider that configured each field. The errors of all providers are
+// returned together, naming the provider.
+func Merge[T any](providers ...Provider[T]) (options.Layers[T], error) {
+	var (
+		layers options.Layers[T]
+		errs   []error
+	)
+	for _, p := range providers {
+		opts, err := p.Load()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("optprovider: %s: %w", p.Name, err))
+			continue
+		}
+		layers = append(layers, options.NewLayer(p.Name, opts...))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return layers, nil
+}
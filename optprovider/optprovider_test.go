@@ -0,0 +1,82 @@
+package optprovider_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+	"github.com/StevenCyb/golang-functional-options/optprovider"
+)
+
+type client struct {
+	BaseURL string
+	Timeout time.Duration
+	Retries int
+}
+
+func withRetries(n int) options.Option[client] {
+	return options.Named("WithRetries", n, func(c *client) {
+		c.Retries = n
+	})
+}
+
+func TestMerge(t *testing.T) {
+	t.Setenv("CLIENT_TIMEOUT", "5s")
+	t.Setenv("CLIENT_BASE_URL", "http://env")
+	path := writeFile(t, "client.json", `{"base_url": "http://file", "timeout": "1s", "retries": 1}`)
+
+	layers, err := optprovider.Merge(
+		optprovider.File[client](path),
+		optprovider.Env[client]("CLIENT"),
+		optprovider.Flags(func() []options.Option[client] {
+			return []options.Option[client]{withRetries(3)}
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(client)
+	p := layers.Apply(got)
+	want := client{BaseURL: "http://env", Timeout: 5 * time.Second, Retries: 3}
+	if *got != want {
+		t.Errorf("Apply() target = %+v, want %+v", *got, want)
+	}
+	wantP := options.Provenance{"BaseURL": "env", "Timeout": "env", "Retries": "flags"}
+	if !reflect.DeepEqual(p, wantP) {
+		t.Errorf("Apply() = %v, want %v", p, wantP)
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	_, err := optprovider.Merge(
+		optprovider.Static("explicit", withRetries(1)),
+		optprovider.File[client](writeFile(t, "client.ini", "")),
+		optprovider.New("broken", func() ([]options.Option[client], error) {
+			return nil, os.ErrNotExist
+		}),
+	)
+	if err == nil {
+		t.Fatal("Merge() succeeded")
+	}
+	for _, want := range []string{
+		`optprovider: file: unknown file extension ".ini"`,
+		"optprovider: broken: file does not exist",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Merge() error = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
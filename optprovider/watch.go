@@ -0,0 +1,123 @@
+package optprovider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+	"github.com/StevenCyb/golang-functional-options/internal/snapshot"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// WatchConfig controls how Watch detects changes.
+type WatchConfig struct {
+	// Interval is how often the files are checked, a second by default.
+	Interval time.Duration
+	// OnError receives the errors of reloading the providers, such as a
+	// file saved with a syntax error. The previous options stay in effect.
+	OnError func(error)
+}
+
+// Interval sets how often Watch checks the files for changes.
+func Interval(d time.Duration) options.Option[WatchConfig] {
+	return options.Named("Interval", d, func(c *WatchConfig) {
+		c.Interval = d
+	})
+}
+
+// OnError sets the function receiving the errors of reloading the
+// providers, which are dropped otherwise.
+func OnError(fn func(error)) options.Option[WatchConfig] {
+	return options.Named("OnError", fn, func(c *WatchConfig) {
+		c.OnError = fn
+	})
+}
+
+// Update is a reload of the providers passed to Watch that changed the
+// configuration.
+type Update[T any] struct {
+	// Layers are the options of the providers after the reload.
+	Layers options.Layers[T]
+	// Changes are the fields configured differently than before the reload,
+	// with A the previous and B the new value.
+	Changes []options.Change
+}
+
+// Option returns an option assigning the changed fields their new value,
+// so a constructed value can be reconfigured with the delta alone:
+//
+//	optprovider.Watch(ctx, providers, func(u optprovider.Update[Client]) {
+//		options.SafeReconfigure(client, u.Option())
+//	})
+func (u Update[T]) Option() options.Option[T] {
+	changes := u.Changes
+	return func(t *T) {
+		v := reflect.ValueOf(t).Elem()
+		for _, c := range changes {
+			f := v
+			for _, name := range strings.Split(c.Field, ".") {
+				f = fields.Access(f.FieldByName(name))
+			}
+			if c.B == nil {
+				f.Set(reflect.Zero(f.Type()))
+			} else {
+				f.Set(reflect.ValueOf(c.B))
+			}
+		}
+	}
+}
+
+// Watch merges the providers like Merge and calls onChange whenever a
+// change of their Files changes the configuration, until ctx is done. It
+// returns the error of the initial merge, or that of ctx. Reloads that
+// fail are reported to OnError and retried on the next change.
+func Watch[T any](ctx context.Context, providers []Provider[T], onChange func(Update[T]), opts ...options.Option[WatchConfig]) error {
+	cfg := options.Apply(&WatchConfig{Interval: time.Second}, opts...)
+	layers, err := Merge(providers...)
+	if err != nil {
+		return err
+	}
+	var files []string
+	for _, p := range providers {
+		files = append(files, p.Files...)
+	}
+
+	last := snapshot.Files(files)
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		current := snapshot.Files(files)
+		if current == last {
+			continue
+		}
+		last = current
+		reloaded, err := Merge(providers...)
+		if err != nil {
+			if cfg.OnError != nil {
+				cfg.OnError(err)
+			}
+			continue
+		}
+		changes := options.Diff(flatten(layers), flatten(reloaded))
+		layers = reloaded
+		if len(changes) > 0 {
+			onChange(Update[T]{Layers: reloaded, Changes: changes})
+		}
+	}
+}
+
+// flatten returns the options of layers in the order they are applied.
+func flatten[T any](layers options.Layers[T]) options.OptionSet[T] {
+	var set options.OptionSet[T]
+	for _, l := range layers {
+		set = append(set, l.Options...)
+	}
+	return set
+}
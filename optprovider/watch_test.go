@@ -0,0 +1,78 @@
+package optprovider_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+	"github.com/StevenCyb/golang-functional-options/optprovider"
+)
+
+func TestWatch(t *testing.T) {
+	path := writeFile(t, "client.json", `{"base_url": "http://a", "retries": 1}`)
+	providers := []optprovider.Provider[client]{optprovider.File[client](path)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan optprovider.Update[client])
+	reloadErrs := make(chan error, 1)
+	done := make(chan error)
+	go func() {
+		done <- optprovider.Watch(ctx, providers, func(u optprovider.Update[client]) {
+			updates <- u
+		}, optprovider.Interval(5*time.Millisecond), optprovider.OnError(func(err error) {
+			select {
+			case reloadErrs <- err:
+			default:
+			}
+		}))
+	}()
+
+	// Writes of a different size are detected regardless of the resolution
+	// of modification times.
+	time.Sleep(20 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(`{`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-reloadErrs:
+		if err == nil {
+			t.Error("OnError received a nil error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no reload error reported")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"base_url": "http://b", "retries": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var u optprovider.Update[client]
+	select {
+	case u = <-updates:
+	case <-time.After(time.Second):
+		t.Fatal("no update")
+	}
+	want := []options.Change{{Field: "BaseURL", A: "http://a", B: "http://b"}}
+	if len(u.Changes) != 1 || u.Changes[0] != want[0] {
+		t.Errorf("Update.Changes = %+v, want %+v", u.Changes, want)
+	}
+	c := client{BaseURL: "http://a", Retries: 5}
+	if u.Option()(&c); c != (client{BaseURL: "http://b", Retries: 5}) {
+		t.Errorf("Update.Option() applied = %+v, want only BaseURL changed", c)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Watch() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWatchInitialError(t *testing.T) {
+	providers := []optprovider.Provider[client]{optprovider.File[client](writeFile(t, "client.ini", ""))}
+	if err := optprovider.Watch(context.Background(), providers, func(optprovider.Update[client]) {}); err == nil {
+		t.Error("Watch() succeeded with a failing provider")
+	}
+}
@@ -0,0 +1,159 @@
+// Package optquery translates query strings into options, so per-request
+// or per-tenant overrides passed through a gateway configure the same
+// struct as every other source:
+//
+//	opts, err := optquery.From[Client](r.URL.Query(), optquery.Allow("timeout", "retry.max_attempts"))
+//	if err != nil {
+//		http.Error(w, err.Error(), http.StatusBadRequest)
+//		return
+//	}
+//	client, err := New(baseURL, options.E(options.Combine(opts...)))
+//
+// Parameters are matched against the field keys of the struct, see the
+// options package, with dots separating the keys of nested structs, such
+// as retry.max_attempts. Repeated parameters set slice fields, other
+// fields take the first value. Values are parsed like the strings of
+// options.FromMap. Parameters without a matching field are ignored, as
+// query strings usually carry more than the options, unless Strict is
+// given.
+//
+// Query strings come from callers, so no field can be set unless Allow
+// names it. The options only convert the values; apply them with ApplyE or
+// a constructor doing so, for the Validate method and the checks of the
+// options of T to reject values out of the accepted range.
+package optquery
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// Config controls how a query string is translated into options.
+type Config struct {
+	// Allow are the dotted keys of the fields that may be set. From fails
+	// when it is empty.
+	Allow []string
+	// Strict reports the parameters without a matching or allowed field
+	// instead of ignoring them.
+	Strict bool
+}
+
+// Allow permits the query to set the fields with the dotted keys, so
+// untrusted input can only override what is meant to be overridden.
+func Allow(keys ...string) options.Option[Config] {
+	return options.Named("Allow", keys, func(c *Config) {
+		c.Allow = append(c.Allow, keys...)
+	})
+}
+
+// Strict makes From fail on parameters without a matching or allowed
+// field.
+func Strict() options.Option[Config] {
+	return options.Named("Strict", true, func(c *Config) {
+		c.Strict = true
+	})
+}
+
+// From returns an option for every parameter of values that sets an allowed
+// field of the struct type T. Every unconvertible value is reported.
+func From[T any](values url.Values, opts ...options.Option[Config]) ([]options.Option[T], error) {
+	cfg := options.Apply(new(Config), opts...)
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("optquery: %s is not a struct", t)
+	}
+	if len(cfg.Allow) == 0 {
+		return nil, errors.New("optquery: no field is allowed, see Allow")
+	}
+
+	params := make([]string, 0, len(values))
+	for param := range values {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	var (
+		doc  = map[string]any{}
+		errs []error
+	)
+	for _, param := range params {
+		key, f, ok := lookup(t, param)
+		switch {
+		case !ok:
+			if cfg.Strict {
+				errs = append(errs, fmt.Errorf("unknown parameter %q", param))
+			}
+			continue
+		case !slices.Contains(cfg.Allow, strings.Join(key, ".")):
+			if cfg.Strict {
+				errs = append(errs, fmt.Errorf("parameter %q is not allowed", param))
+			}
+			continue
+		}
+
+		m := doc
+		for _, k := range key[:len(key)-1] {
+			sub, ok := m[k].(map[string]any)
+			if !ok {
+				sub = map[string]any{}
+				m[k] = sub
+			}
+			m = sub
+		}
+		var value any = values[param][0]
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8 && !fields.TextUnmarshaler(f.Type) {
+			all := make([]any, len(values[param]))
+			for i, v := range values[param] {
+				all[i] = v
+			}
+			value = all
+		}
+		m[key[len(key)-1]] = value
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("optquery: %w", errors.Join(errs...))
+	}
+
+	result, err := options.FromMap[T](doc)
+	if err != nil {
+		return nil, fmt.Errorf("optquery: %w", err)
+	}
+	return result, nil
+}
+
+// lookup returns the field keys leading from the struct type t to the field
+// of the dotted parameter name, and that field. Only the fields of nested
+// structs declared in the package of t are descended into.
+func lookup(t reflect.Type, param string) ([]string, fields.Field, bool) {
+	var (
+		key []string
+		f   fields.Field
+	)
+	current := t
+	for i, part := range strings.Split(param, ".") {
+		if i > 0 {
+			nested := f.Type
+			if nested.Kind() == reflect.Pointer {
+				nested = nested.Elem()
+			}
+			if nested.Kind() != reflect.Struct || nested.PkgPath() != t.PkgPath() || fields.TextUnmarshaler(f.Type) {
+				return nil, fields.Field{}, false
+			}
+			current = nested
+		}
+		var ok bool
+		if f, ok = fields.Lookup(fields.Fields(current), part); !ok {
+			return nil, fields.Field{}, false
+		}
+		key = append(key, f.Key)
+	}
+	return key, f, true
+}
@@ -0,0 +1,84 @@
+package optquery_test
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+	"github.com/StevenCyb/golang-functional-options/optquery"
+)
+
+type retry struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+type client struct {
+	BaseURL string
+	Timeout time.Duration
+	Tags    []string
+	Retry   *retry
+}
+
+func TestFrom(t *testing.T) {
+	allow := optquery.Allow("timeout", "tags", "retry.max_attempts")
+	tests := []struct {
+		name    string
+		query   string
+		opts    []options.Option[optquery.Config]
+		want    client
+		wantErr string
+	}{
+		{name: "empty", opts: []options.Option[optquery.Config]{allow}},
+		{
+			name:  "allowed",
+			query: "timeout=5s&timeout=6s&tags=a&tags=b&retry.max_attempts=3",
+			opts:  []options.Option[optquery.Config]{allow},
+			want:  client{Timeout: 5 * time.Second, Tags: []string{"a", "b"}, Retry: &retry{MaxAttempts: 3}},
+		},
+		{
+			name:  "unlisted parameter",
+			query: "base_url=http://evil&page=2&timeout=5s",
+			opts:  []options.Option[optquery.Config]{allow},
+			want:  client{Timeout: 5 * time.Second},
+		},
+		{
+			name:  "nested key not allowed",
+			query: "retry.backoff=1h&retry.max_attempts=3",
+			opts:  []options.Option[optquery.Config]{allow},
+			want:  client{Retry: &retry{MaxAttempts: 3}},
+		},
+		{
+			name:    "strict",
+			query:   "base_url=http://evil&page=2&retry.backoff=1h&tags.x=1&timeout=5s",
+			opts:    []options.Option[optquery.Config]{allow, optquery.Strict()},
+			wantErr: "optquery: parameter \"base_url\" is not allowed\nunknown parameter \"page\"\nparameter \"retry.backoff\" is not allowed\nunknown parameter \"tags.x\"",
+		},
+		{name: "invalid value", query: "timeout=x", opts: []options.Option[optquery.Config]{allow}, wantErr: "optquery: key \"timeout\": time: invalid duration \"x\""},
+		{name: "nothing allowed", query: "timeout=5s", wantErr: "optquery: no field is allowed, see Allow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts, err := optquery.From[client](values, tt.opts...)
+			if got := errorString(err); got != tt.wantErr {
+				t.Fatalf("From() error = %q, want %q", got, tt.wantErr)
+			}
+			if got := options.Apply(new(client), opts...); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Apply(From()) = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,119 @@
+package optremote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Consul reads values from the KV store of a Consul agent through its HTTP
+// API, watching them with blocking queries.
+type Consul struct {
+	// Address is the URL of the agent, such as http://127.0.0.1:8500.
+	Address string
+	// Token is the ACL token sent with every request, if any.
+	Token string
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+	// Wait is how long a blocking query waits for a change before it is
+	// repeated, five minutes when zero.
+	Wait time.Duration
+}
+
+// Get returns the values stored below prefix.
+func (c *Consul) Get(ctx context.Context, prefix string) (map[string]string, error) {
+	values, _, err := c.list(ctx, prefix, 0)
+	return values, err
+}
+
+// Watch calls fn with the values stored below prefix and again whenever
+// they change.
+func (c *Consul) Watch(ctx context.Context, prefix string, fn func(map[string]string)) error {
+	last, index, err := c.list(ctx, prefix, 0)
+	if err != nil {
+		return err
+	}
+	fn(last)
+	for {
+		values, next, err := c.list(ctx, prefix, index)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		// Consul asks clients to start over when the index goes backwards,
+		// and an index of 1 does so without ever blocking on zero.
+		if next < index || next == 0 {
+			next = 1
+		}
+		index = next
+		if !maps.Equal(values, last) {
+			last = values
+			fn(values)
+		}
+	}
+}
+
+// list returns the values below prefix and the index of the KV store. A
+// non-zero index makes the query block until the store moves past it.
+func (c *Consul) list(ctx context.Context, prefix string, index uint64) (map[string]string, uint64, error) {
+	query := url.Values{"recurse": {"true"}}
+	if index > 0 {
+		wait := c.Wait
+		if wait == 0 {
+			wait = 5 * time.Minute
+		}
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", wait.String())
+	}
+	endpoint := strings.TrimSuffix(c.Address, "/") + "/v1/kv/" + dir(prefix) + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	next, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	values := map[string]string{}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// Nothing is stored below the prefix yet.
+		return values, next, nil
+	default:
+		return nil, 0, fmt.Errorf("consul: %s", resp.Status)
+	}
+	var entries []struct {
+		Key   string
+		Value []byte
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, 0, fmt.Errorf("consul: %w", err)
+	}
+	for _, e := range entries {
+		key := strings.TrimPrefix(e.Key, dir(prefix))
+		// Folders are stored as keys ending in a slash without a value.
+		if key != "" && !strings.HasSuffix(key, "/") {
+			values[key] = string(e.Value)
+		}
+	}
+	return values, next, nil
+}
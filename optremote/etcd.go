@@ -0,0 +1,146 @@
+package optremote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Etcd reads values from an etcd v3 cluster through its JSON gateway,
+// watching them with a watch stream.
+type Etcd struct {
+	// Endpoint is the URL of a member, such as http://127.0.0.1:2379.
+	Endpoint string
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+}
+
+// Get returns the values stored below prefix.
+func (e *Etcd) Get(ctx context.Context, prefix string) (map[string]string, error) {
+	values, _, err := e.rangeOf(ctx, prefix)
+	return values, err
+}
+
+// Watch calls fn with the values stored below prefix and again whenever
+// they change. The watch starts after the revision of the first values.
+func (e *Etcd) Watch(ctx context.Context, prefix string, fn func(map[string]string)) error {
+	values, revision, err := e.rangeOf(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	fn(values)
+	key, end := keyRange(prefix)
+	resp, err := e.post(ctx, "/v3/watch", map[string]any{
+		"create_request": map[string]any{
+			"key":            key,
+			"range_end":      end,
+			"start_revision": strconv.FormatInt(revision+1, 10),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var msg struct {
+			Result struct {
+				Events   []json.RawMessage `json:"events"`
+				Canceled bool              `json:"canceled"`
+			} `json:"result"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := dec.Decode(&msg); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("etcd: %w", err)
+		}
+		switch {
+		case msg.Error != nil:
+			return fmt.Errorf("etcd: %s", msg.Error.Message)
+		case msg.Result.Canceled:
+			return fmt.Errorf("etcd: watch canceled")
+		case len(msg.Result.Events) == 0:
+			// The confirmation of the watch and progress notifications.
+			continue
+		}
+		values, _, err := e.rangeOf(ctx, prefix)
+		if err != nil {
+			return err
+		}
+		fn(values)
+	}
+}
+
+// rangeOf returns the values below prefix and the revision of the store.
+func (e *Etcd) rangeOf(ctx context.Context, prefix string) (map[string]string, int64, error) {
+	key, end := keyRange(prefix)
+	resp, err := e.post(ctx, "/v3/kv/range", map[string]any{"key": key, "range_end": end})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	// The gateway encodes 64 bit integers as strings and bytes as base64.
+	var result struct {
+		Header struct {
+			Revision int64 `json:"revision,string"`
+		} `json:"header"`
+		Kvs []struct {
+			Key   []byte `json:"key"`
+			Value []byte `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, 0, fmt.Errorf("etcd: %w", err)
+	}
+	values := map[string]string{}
+	for _, kv := range result.Kvs {
+		values[strings.TrimPrefix(string(kv.Key), dir(prefix))] = string(kv.Value)
+	}
+	return values, result.Header.Revision, nil
+}
+
+// post sends body as JSON to the gateway path of the endpoint.
+func (e *Etcd) post(ctx context.Context, path string, body any) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(e.Endpoint, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("etcd: %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// keyRange returns the range of the keys below prefix, from the prefix to
+// the prefix with its last byte incremented, as []byte so they are sent as
+// base64.
+func keyRange(prefix string) (key, end []byte) {
+	key = []byte(dir(prefix))
+	end = bytes.Clone(key)
+	end[len(end)-1]++
+	return key, end
+}
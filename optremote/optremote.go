@@ -0,0 +1,132 @@
+// Package optremote sources options from centralized configuration
+// services such as etcd and Consul, and refreshes them when the stored
+// values change:
+//
+//	store := &optremote.Consul{Address: "http://consul:8500"}
+//	opts, err := optremote.Options[Client](ctx, store, "config/client")
+//	if err != nil {
+//		return err
+//	}
+//	client := clientDefaults.Apply(new(Client), opts...)
+//
+// The keys below the prefix are the field keys of the struct, see the
+// options package, with slashes separating the keys of nested structs,
+// such as config/client/retry/max_attempts. Values are parsed like the
+// strings of options.FromMap. Keys without a matching field are ignored,
+// so services can store more than one struct below a prefix.
+package optremote
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+	"github.com/StevenCyb/golang-functional-options/options"
+	"github.com/StevenCyb/golang-functional-options/optprovider"
+)
+
+// RemoteProvider is a key/value store holding configuration values. Keys
+// are given relative to the prefix, such as retry/max_attempts below
+// config/client.
+type RemoteProvider interface {
+	// Get returns the values stored below prefix.
+	Get(ctx context.Context, prefix string) (map[string]string, error)
+	// Watch calls fn with the values stored below prefix, first with the
+	// current ones and then whenever they change, until ctx is done or the
+	// store fails. The first call and the start of the watch come from the
+	// same read, so no change can fall between them.
+	Watch(ctx context.Context, prefix string, fn func(map[string]string)) error
+}
+
+// Options returns an option for every value stored below prefix that sets
+// a field of the struct type T.
+func Options[T any](ctx context.Context, r RemoteProvider, prefix string) ([]options.Option[T], error) {
+	values, err := r.Get(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("optremote: %s: %w", prefix, err)
+	}
+	return translate[T](prefix, values)
+}
+
+// Provider creates a provider called remote of the values stored below
+// prefix, to be merged with other sources by optprovider.Merge.
+func Provider[T any](ctx context.Context, r RemoteProvider, prefix string) optprovider.Provider[T] {
+	return optprovider.New("remote", func() ([]options.Option[T], error) {
+		return Options[T](ctx, r, prefix)
+	})
+}
+
+// Watch calls onChange whenever a change of the values below prefix changes
+// the configuration, until ctx is done or the store fails. The update holds
+// a single layer called remote. Changes that cannot be translated into
+// options are skipped, while Watch fails when the values it starts from
+// cannot.
+func Watch[T any](ctx context.Context, r RemoteProvider, prefix string, onChange func(optprovider.Update[T])) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		current []options.Option[T]
+		started bool
+		initial error
+	)
+	err := r.Watch(ctx, prefix, func(values map[string]string) {
+		opts, err := translate[T](prefix, values)
+		if !started {
+			started = true
+			current, initial = opts, err
+			if err != nil {
+				cancel()
+			}
+			return
+		}
+		if err != nil {
+			return
+		}
+		changes := options.Diff(options.OptionSet[T](current), options.OptionSet[T](opts))
+		current = opts
+		if len(changes) > 0 {
+			onChange(optprovider.Update[T]{
+				Layers:  options.Layers[T]{options.NewLayer("remote", opts...)},
+				Changes: changes,
+			})
+		}
+	})
+	if initial != nil {
+		return initial
+	}
+	if err != nil {
+		return fmt.Errorf("optremote: %s: %w", prefix, err)
+	}
+	return nil
+}
+
+// translate converts the values stored below prefix into options.
+func translate[T any](prefix string, values map[string]string) ([]options.Option[T], error) {
+	doc := map[string]any{}
+	for key, value := range values {
+		m := doc
+		parts := strings.Split(strings.Trim(key, "/"), "/")
+		for _, part := range parts[:len(parts)-1] {
+			sub, ok := m[part].(map[string]any)
+			if !ok {
+				sub = map[string]any{}
+				m[part] = sub
+			}
+			m = sub
+		}
+		m[parts[len(parts)-1]] = value
+	}
+	opts, err := options.FromMap[T](fields.Known(reflect.TypeFor[T](), doc))
+	if err != nil {
+		return nil, fmt.Errorf("optremote: %s: %w", prefix, err)
+	}
+	return opts, nil
+}
+
+// dir returns prefix with a single trailing slash, so values below
+// config/client do not include those of config/client2.
+func dir(prefix string) string {
+	return strings.TrimSuffix(prefix, "/") + "/"
+}
@@ -0,0 +1,90 @@
+package optremote_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+	"github.com/StevenCyb/golang-functional-options/optprovider"
+	"github.com/StevenCyb/golang-functional-options/optremote"
+)
+
+type retry struct {
+	MaxAttempts int
+}
+
+type client struct {
+	BaseURL string
+	Retry   retry
+}
+
+// memory is a store holding values, whose Watch reports them and then each
+// of changes in turn.
+type memory struct {
+	values  map[string]string
+	changes []map[string]string
+}
+
+func (m *memory) Get(ctx context.Context, prefix string) (map[string]string, error) {
+	return m.values, nil
+}
+
+func (m *memory) Watch(ctx context.Context, prefix string, fn func(map[string]string)) error {
+	fn(m.values)
+	for _, values := range m.changes {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fn(values)
+	}
+	return nil
+}
+
+func TestOptions(t *testing.T) {
+	store := &memory{values: map[string]string{"base_url": "http://a", "retry/max_attempts": "3", "other": "x"}}
+	opts, err := optremote.Options[client](context.Background(), store, "config/client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := client{BaseURL: "http://a", Retry: retry{MaxAttempts: 3}}
+	if got := options.Apply(new(client), opts...); *got != want {
+		t.Errorf("Apply(Options()) = %+v, want %+v", *got, want)
+	}
+
+	store.values = map[string]string{"retry/max_attempts": "x"}
+	if _, err := optremote.Options[client](context.Background(), store, "config/client"); err == nil || !strings.HasPrefix(err.Error(), "optremote: config/client: ") {
+		t.Errorf("Options() error = %v, want it to name the prefix", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	store := &memory{
+		values: map[string]string{"base_url": "http://a"},
+		changes: []map[string]string{
+			{"base_url": "http://a"},
+			{"base_url": "http://a", "retry/max_attempts": "x"},
+			{"base_url": "http://b"},
+		},
+	}
+	var changes [][]options.Change
+	err := optremote.Watch(context.Background(), store, "config/client", func(u optprovider.Update[client]) {
+		changes = append(changes, u.Changes)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]options.Change{{{Field: "BaseURL", A: "http://a", B: "http://b"}}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Watch() updates = %+v, want %+v", changes, want)
+	}
+
+	store = &memory{values: map[string]string{"retry/max_attempts": "x"}, changes: []map[string]string{{"base_url": "http://b"}}}
+	err = optremote.Watch(context.Background(), store, "config/client", func(u optprovider.Update[client]) {
+		t.Errorf("Watch() reported %+v after failing to start", u.Changes)
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "optremote: config/client: ") {
+		t.Errorf("Watch() error = %v, want it to name the prefix", err)
+	}
+}
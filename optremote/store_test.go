@@ -0,0 +1,119 @@
+package optremote_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/optremote"
+)
+
+func TestConsulGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Consul-Token"); got != "secret" {
+			t.Errorf("X-Consul-Token = %q, want secret", got)
+		}
+		if r.URL.Path != "/v1/kv/config/client/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Consul-Index", "7")
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"Key": "config/client/retry/", "Value": nil},
+			{"Key": "config/client/base_url", "Value": []byte("http://a")},
+		})
+	}))
+	defer srv.Close()
+
+	store := &optremote.Consul{Address: srv.URL + "/", Token: "secret"}
+	values, err := store.Get(context.Background(), "config/client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"base_url": "http://a"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Get() = %v, want %v", values, want)
+	}
+	if values, err := store.Get(context.Background(), "config/other"); err != nil || len(values) != 0 {
+		t.Errorf("Get(missing) = %v, %v, want no values", values, err)
+	}
+}
+
+func TestEtcdGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Key      []byte `json:"key"`
+			RangeEnd []byte `json:"range_end"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/v3/kv/range" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if string(req.Key) != "config/client/" || string(req.RangeEnd) != "config/client0" {
+			t.Errorf("range = %q to %q, want config/client/ to config/client0", req.Key, req.RangeEnd)
+		}
+		enc := base64.StdEncoding.EncodeToString
+		json.NewEncoder(w).Encode(map[string]any{
+			"header": map[string]any{"revision": "12"},
+			"kvs":    []map[string]any{{"key": enc([]byte("config/client/base_url")), "value": enc([]byte("http://a"))}},
+		})
+	}))
+	defer srv.Close()
+
+	values, err := (&optremote.Etcd{Endpoint: srv.URL}).Get(context.Background(), "config/client/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"base_url": "http://a"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Get() = %v, want %v", values, want)
+	}
+	if _, err := (&optremote.Etcd{Endpoint: srv.URL + "/missing"}).Get(context.Background(), "config/client"); err == nil {
+		t.Error("Get() succeeded on a failing gateway")
+	}
+}
+
+func TestEtcdWatch(t *testing.T) {
+	ranges := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := base64.StdEncoding.EncodeToString
+		switch r.URL.Path {
+		case "/v3/kv/range":
+			ranges++
+			json.NewEncoder(w).Encode(map[string]any{
+				"header": map[string]any{"revision": strconv.Itoa(11 + ranges)},
+				"kvs":    []map[string]any{{"key": enc([]byte("config/client/base_url")), "value": enc([]byte("http://" + strconv.Itoa(ranges)))}},
+			})
+		case "/v3/watch":
+			var req struct {
+				CreateRequest struct {
+					StartRevision string `json:"start_revision"`
+				} `json:"create_request"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			if got := req.CreateRequest.StartRevision; got != "13" {
+				t.Errorf("start_revision = %s, want 13", got)
+			}
+			w.Write([]byte(`{"result":{"created":true}}` + "\n" + `{"result":{"events":[{}]}}` + "\n"))
+		}
+	}))
+	defer srv.Close()
+
+	var got []map[string]string
+	err := (&optremote.Etcd{Endpoint: srv.URL}).Watch(context.Background(), "config/client", func(values map[string]string) {
+		got = append(got, values)
+	})
+	if err == nil {
+		t.Error("Watch() succeeded after the stream ended")
+	}
+	want := []map[string]string{{"base_url": "http://1"}, {"base_url": "http://2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Watch() values = %v, want %v", got, want)
+	}
+	if ranges != 2 {
+		t.Errorf("Watch() read the range %d times, want 2", ranges)
+	}
+}
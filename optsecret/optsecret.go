@@ -0,0 +1,130 @@
+// Package optsecret resolves secret material while options are applied, so
+// tokens and passwords are referenced by source and configuration rather
+// than written into them:
+//
+//	optsecret.Register("vault", &optsecret.Vault{Address: "https://vault:8200"})
+//
+//	err := options.ApplyCtx(ctx, client,
+//		optsecret.Resolve("vault://secret/api#token", func(c *Client, token string) {
+//			c.header["Authorization"] = "Bearer " + token
+//		}),
+//	)
+//
+// A reference is a URL whose scheme selects the SecretStore, whose host and
+// path name the secret and whose fragment selects a key within it. The env
+// and file schemes are registered by default: env://API_TOKEN reads the
+// environment variable API_TOKEN and file:///run/secrets/token the file,
+// without its trailing newline.
+package optsecret
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// SecretStore fetches secret material.
+type SecretStore interface {
+	// Secret returns the secret at path, or the value of key within it
+	// when key is not empty.
+	Secret(ctx context.Context, path, key string) (string, error)
+}
+
+// StoreFunc adapts a function to a SecretStore.
+type StoreFunc func(ctx context.Context, path, key string) (string, error)
+
+// Secret calls f.
+func (f StoreFunc) Secret(ctx context.Context, path, key string) (string, error) {
+	return f(ctx, path, key)
+}
+
+var (
+	storesMu sync.RWMutex
+	stores   = map[string]SecretStore{
+		"env":  StoreFunc(envSecret),
+		"file": StoreFunc(fileSecret),
+	}
+)
+
+// Register makes store resolve the references with scheme. It panics when
+// scheme is registered twice.
+func Register(scheme string, store SecretStore) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	if _, dup := stores[scheme]; dup {
+		panic("optsecret: Register called twice for scheme " + scheme)
+	}
+	stores[scheme] = store
+}
+
+// Resolve returns an option fetching the secret of ref from the store
+// registered for its scheme and passing it to set. The secret is fetched
+// when the option is applied, with the context of ApplyCtx.
+func Resolve[T any](ref string, set func(t *T, secret string)) options.OptionCtx[T] {
+	return func(ctx context.Context, t *T) error {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return &options.OptionError{Name: ref, Err: err}
+		}
+		storesMu.RLock()
+		store, ok := stores[u.Scheme]
+		storesMu.RUnlock()
+		if !ok {
+			return &options.OptionError{Name: ref, Err: fmt.Errorf("no secret store registered for scheme %q", u.Scheme)}
+		}
+		return resolve(ctx, store, ref, u, t, set)
+	}
+}
+
+// ResolveWith is like Resolve but fetches the secret from store, ignoring
+// the scheme of ref.
+func ResolveWith[T any](store SecretStore, ref string, set func(t *T, secret string)) options.OptionCtx[T] {
+	return func(ctx context.Context, t *T) error {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return &options.OptionError{Name: ref, Err: err}
+		}
+		return resolve(ctx, store, ref, u, t, set)
+	}
+}
+
+// resolve fetches the secret of the parsed reference u from store. Errors
+// are named after the reference, never mentioning the secret.
+func resolve[T any](ctx context.Context, store SecretStore, ref string, u *url.URL, t *T, set func(*T, string)) error {
+	secret, err := store.Secret(ctx, strings.TrimPrefix(u.Host+u.Path, "/"), u.Fragment)
+	if err != nil {
+		return &options.OptionError{Name: ref, Err: err}
+	}
+	set(t, secret)
+	return nil
+}
+
+// envSecret reads the environment variable path.
+func envSecret(_ context.Context, path, key string) (string, error) {
+	if key != "" {
+		return "", fmt.Errorf("environment variables have no keys")
+	}
+	secret, ok := os.LookupEnv(path)
+	if !ok {
+		return "", fmt.Errorf("%s is not set", path)
+	}
+	return secret, nil
+}
+
+// fileSecret reads the file at the absolute path, as mounted by Docker and
+// Kubernetes secrets.
+func fileSecret(_ context.Context, path, key string) (string, error) {
+	if key != "" {
+		return "", fmt.Errorf("files have no keys")
+	}
+	data, err := os.ReadFile("/" + path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
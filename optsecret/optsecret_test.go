@@ -0,0 +1,90 @@
+package optsecret_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+	"github.com/StevenCyb/golang-functional-options/optsecret"
+)
+
+type client struct {
+	token string
+}
+
+func setToken(c *client, token string) {
+	c.token = token
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("OPTSECRET_TOKEN", "from env")
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{ref: "env://OPTSECRET_TOKEN", want: "from env"},
+		{ref: "file://" + filepath.ToSlash(path), want: "from file"},
+		{ref: "env://OPTSECRET_MISSING", wantErr: "option env://OPTSECRET_MISSING: OPTSECRET_MISSING is not set"},
+		{ref: "env://OPTSECRET_TOKEN#key", wantErr: "option env://OPTSECRET_TOKEN#key: environment variables have no keys"},
+		{ref: "unknown://token", wantErr: `option unknown://token: no secret store registered for scheme "unknown"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got := new(client)
+			err := options.ApplyCtx(context.Background(), got, optsecret.Resolve(tt.ref, setToken))
+			if err != nil {
+				if err.Error() != tt.wantErr {
+					t.Errorf("ApplyCtx() error = %q, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if tt.wantErr != "" {
+				t.Fatalf("ApplyCtx() succeeded, want %q", tt.wantErr)
+			}
+			if got.token != tt.want {
+				t.Errorf("token = %q, want %q", got.token, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveWith(t *testing.T) {
+	var gotPath, gotKey string
+	store := optsecret.StoreFunc(func(ctx context.Context, path, key string) (string, error) {
+		gotPath, gotKey = path, key
+		if key == "missing" {
+			return "", errors.New("no such key")
+		}
+		return "s3cr3t", nil
+	})
+	got := new(client)
+	if err := options.ApplyCtx(context.Background(), got, optsecret.ResolveWith(store, "any://secret/api#token", setToken)); err != nil {
+		t.Fatal(err)
+	}
+	if got.token != "s3cr3t" || gotPath != "secret/api" || gotKey != "token" {
+		t.Errorf("resolved %q from %q#%q, want s3cr3t from secret/api#token", got.token, gotPath, gotKey)
+	}
+
+	err := options.ApplyCtx(context.Background(), got, optsecret.ResolveWith(store, "any://secret/api#missing", setToken))
+	if err == nil || strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("ApplyCtx() error = %v, want an error without the secret", err)
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() did not panic for a registered scheme")
+		}
+	}()
+	optsecret.Register("env", optsecret.StoreFunc(nil))
+}
@@ -0,0 +1,79 @@
+package optsecret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Vault reads secrets from the KV version 2 secrets engine of HashiCorp
+// Vault through its HTTP API. The first element of the path is the mount
+// of the engine, so vault://secret/api#token reads the key token of the
+// secret api in the engine mounted at secret.
+type Vault struct {
+	// Address is the URL of the server, VAULT_ADDR when empty.
+	Address string
+	// Token authenticates the requests, VAULT_TOKEN when empty.
+	Token string
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+}
+
+// Secret returns the value of key in the secret at path. Without a key, the
+// secret must hold a single value.
+func (v *Vault) Secret(ctx context.Context, path, key string) (string, error) {
+	mount, name, ok := strings.Cut(path, "/")
+	if !ok {
+		return "", fmt.Errorf("vault: %q has no mount", path)
+	}
+	address, token := v.Address, v.Token
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(address, "/")+"/v1/"+mount+"/data/"+name, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault: %s", resp.Status)
+	}
+
+	var result struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	values := result.Data.Data
+	if key == "" {
+		if len(values) != 1 {
+			return "", fmt.Errorf("vault: %s holds %d values, select one with a key", path, len(values))
+		}
+		for _, value := range values {
+			return fmt.Sprint(value), nil
+		}
+	}
+	value, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("vault: %s has no key %q", path, key)
+	}
+	return fmt.Sprint(value), nil
+}
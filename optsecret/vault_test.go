@@ -0,0 +1,64 @@
+package optsecret_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StevenCyb/golang-functional-options/optsecret"
+)
+
+func TestVault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		data := map[string]map[string]any{
+			"/v1/secret/data/api":  {"token": "s3cr3t", "user": "api"},
+			"/v1/secret/data/port": {"port": 8200},
+		}[r.URL.Path]
+		if data == nil {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": data}})
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name, path, key string
+		token           string
+		want            string
+		wantErr         string
+	}{
+		{name: "key", path: "secret/api", key: "token", want: "s3cr3t"},
+		{name: "single value", path: "secret/port", want: "8200"},
+		{name: "several values", path: "secret/api", wantErr: "vault: secret/api holds 2 values, select one with a key"},
+		{name: "missing key", path: "secret/api", key: "password", wantErr: `vault: secret/api has no key "password"`},
+		{name: "missing secret", path: "secret/db", key: "password", wantErr: "vault: 404 Not Found"},
+		{name: "no mount", path: "api", wantErr: `vault: "api" has no mount`},
+		{name: "token", path: "secret/api", key: "token", token: "guest", wantErr: "vault: 403 Forbidden"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := tt.token
+			if token == "" {
+				token = "root"
+			}
+			store := &optsecret.Vault{Address: srv.URL, Token: token}
+			got, err := store.Secret(context.Background(), tt.path, tt.key)
+			if err != nil {
+				if err.Error() != tt.wantErr {
+					t.Errorf("Secret() error = %q, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if tt.wantErr != "" || got != tt.want {
+				t.Errorf("Secret() = %q, want %q, %q", got, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
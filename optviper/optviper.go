@@ -0,0 +1,61 @@
+// Package optviper converts the settings of a github.com/spf13/viper
+// instance into options, so services configured through viper adopt
+// options without changing how they load their configuration:
+//
+//	v := viper.New()
+//	v.SetConfigFile("client.yaml")
+//	v.AutomaticEnv()
+//	if err := v.ReadInConfig(); err != nil {
+//		return err
+//	}
+//	opts, err := optviper.From[Client](v)
+//
+// Settings are matched against the field keys of the struct as described
+// for options.FromMap, and nested settings set the fields of nested
+// structs. Settings without a matching field are ignored, so one viper
+// instance can configure several structs, unless Strict is given. Note
+// that viper lower-cases all keys, including those of map fields.
+package optviper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/StevenCyb/golang-functional-options/internal/fields"
+	"github.com/StevenCyb/golang-functional-options/options"
+)
+
+// Settings is the part of *viper.Viper read by From, so this package does
+// not depend on viper.
+type Settings interface {
+	AllSettings() map[string]any
+}
+
+// Config controls how the settings are translated into options.
+type Config struct {
+	// Strict reports the settings without a matching field instead of
+	// ignoring them.
+	Strict bool
+}
+
+// Strict makes From fail on settings without a matching field.
+func Strict() options.Option[Config] {
+	return options.Named("Strict", true, func(c *Config) {
+		c.Strict = true
+	})
+}
+
+// From returns an option for every setting of v that sets a field of the
+// struct type T. Every unconvertible value is reported.
+func From[T any](v Settings, opts ...options.Option[Config]) ([]options.Option[T], error) {
+	cfg := options.Apply(new(Config), opts...)
+	settings := v.AllSettings()
+	if !cfg.Strict {
+		settings = fields.Known(reflect.TypeFor[T](), settings)
+	}
+	result, err := options.FromMap[T](settings)
+	if err != nil {
+		return nil, fmt.Errorf("optviper: %w", err)
+	}
+	return result, nil
+}
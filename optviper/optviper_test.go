@@ -0,0 +1,50 @@
+package optviper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StevenCyb/golang-functional-options/options"
+	"github.com/StevenCyb/golang-functional-options/optviper"
+)
+
+// settings stands in for a *viper.Viper.
+type settings map[string]any
+
+func (s settings) AllSettings() map[string]any {
+	return s
+}
+
+type retry struct {
+	MaxAttempts int
+}
+
+type client struct {
+	BaseURL string
+	Timeout time.Duration
+	Retry   retry
+}
+
+func TestFrom(t *testing.T) {
+	v := settings{
+		"base_url": "http://a",
+		"timeout":  "5s",
+		"retry":    map[string]any{"max_attempts": 3, "backoff": "1s"},
+		"server":   map[string]any{"port": 8080},
+	}
+	opts, err := optviper.From[client](v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := client{BaseURL: "http://a", Timeout: 5 * time.Second, Retry: retry{MaxAttempts: 3}}
+	if got := options.Apply(new(client), opts...); *got != want {
+		t.Errorf("Apply(From()) = %+v, want %+v", *got, want)
+	}
+
+	if _, err := optviper.From[client](v, optviper.Strict()); err == nil {
+		t.Error("From(Strict()) succeeded with unknown settings")
+	}
+	if _, err := optviper.From[client](settings{"timeout": "x"}); err == nil {
+		t.Error("From() succeeded with an invalid duration")
+	}
+}